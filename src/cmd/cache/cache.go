@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"src/cmd/smgr/pkg/cache"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type pruneConfig struct {
+	CacheDir  string `san:"trim"`
+	OlderThan time.Duration
+}
+
+func NewCacheCommand() *cobra.Command {
+	var cacheCmd = &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the on-disk tag cache of fetch.",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+	cacheCmd.AddCommand(newPruneCommand())
+
+	return cacheCmd
+}
+
+func newPruneCommand() *cobra.Command {
+	config := &pruneConfig{}
+	var pruneCmd = &cobra.Command{
+		Use:   "prune",
+		Short: "Remove the cached tag lists.",
+		Long: `Remove the cached tag lists of the fetch command. With
+--older-than, only the tag lists that were not used for
+longer than the duration are removed.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunPrune(config, cmd)
+		},
+	}
+	pruneCmd.Flags().StringVar(&config.CacheDir, "cache-dir", "", "The directory of the tag cache, defaults to smgr in the user cache directory (optional)")
+	pruneCmd.Flags().DurationVar(&config.OlderThan, "older-than", 0, "Only remove the tag lists unused for longer than this duration e.g. 168h (optional)")
+
+	return pruneCmd
+}
+
+func RunPrune(config *pruneConfig, cmd *cobra.Command) error {
+	dir := config.CacheDir
+	if dir == "" {
+		defaultDir, err := cache.DefaultDir()
+		if err != nil {
+			return err
+		}
+		dir = defaultDir
+	}
+
+	removed, err := cache.New(dir, 0).Prune(config.OlderThan)
+	if err != nil {
+		return err
+	}
+	cmd.Printf("Removed %d cached tag lists from %s\n", removed, dir)
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCacheCommand(t *testing.T) {
+	t.Run("Command has a prune subcommand", func(t *testing.T) {
+		cmd := NewCacheCommand()
+		assert.Equal(t, "cache", cmd.Name())
+
+		pruneCmd, _, err := cmd.Find([]string{"prune"})
+		require.NoError(t, err)
+		assert.Equal(t, "prune", pruneCmd.Name())
+		assert.NotNil(t, pruneCmd.Flags().Lookup("older-than"))
+		assert.NotNil(t, pruneCmd.Flags().Lookup("cache-dir"))
+	})
+
+	t.Run("Prune removes the unused tag lists", func(t *testing.T) {
+		dir := t.TempDir()
+		old, recent := filepath.Join(dir, "old.json"), filepath.Join(dir, "recent.json")
+		require.NoError(t, os.WriteFile(old, []byte("{}"), 0o600))
+		require.NoError(t, os.WriteFile(recent, []byte("{}"), 0o600))
+		require.NoError(t, os.Chtimes(old, time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour)))
+
+		output := new(bytes.Buffer)
+		cmd := NewCacheCommand()
+		cmd.SetOut(output)
+		cmd.SetErr(output)
+		cmd.SetArgs([]string{"prune", "--cache-dir", dir, "--older-than", "24h"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "Removed 1 cached tag lists from "+dir+"\n", output.String())
+		assert.NoFileExists(t, old)
+		assert.FileExists(t, recent)
+	})
+}
@@ -1,10 +1,22 @@
 package fetch
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
 	"src/cmd/smgr/cmd/filter"
 	"src/cmd/smgr/cmd/utils"
+	"src/cmd/smgr/datasource/file"
+	"src/cmd/smgr/datasource/npm"
 	datasourceUtils "src/cmd/smgr/datasource/utils"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/pkg/cache"
+	"src/cmd/smgr/pkg/fetch"
+	filterPkg "src/cmd/smgr/pkg/filter"
+	sharedUtils "src/cmd/smgr/utils"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/klog"
@@ -14,7 +26,28 @@ type config struct {
 	Token      string `san:"trim"`
 	Repository string `san:"trim"`
 	Owner      string `san:"trim"`
+	Username   string `san:"trim"`
 	Platform   string `san:"trim"`
+	BaseURL    string `san:"trim"`
+	TokenType  string `san:"trim"`
+	APIURL     string `san:"trim"`
+	UploadURL  string `san:"trim"`
+	CABundle   string `san:"trim"`
+	Ref        string `san:"trim"`
+	TagPrefix  string `san:"trim"`
+	Chart      string `san:"trim"`
+	AppVersion bool
+	Sources    []string
+	Kind       string `san:"trim"`
+	NoDrafts   bool
+	NoYanked   bool
+	DistTags   bool
+	LatestOnly bool
+	NoCache    bool
+	CacheTTL   time.Duration
+	CacheDir   string `san:"trim"`
+	namespace  models.Namespace
+	prefix     models.PrefixStyle
 	dryRun     bool
 }
 
@@ -23,11 +56,11 @@ func NewFetchCommand(filterArgs *filter.FilterArgs) *cobra.Command {
 	var fetchCmd = &cobra.Command{
 		Use:   "fetch",
 		Short: "Fetch semver tags from a repository.",
-		Long: `Fetch semver tags from a repository and
-sorted by highest version first. Fetch also supports all
-the filters from the filter command. If the --versions
-flag is set, the versions passed will be merged with the
-fetched versions.`,
+		Long: `Fetch semver tags from a repository,
+sorted by ascending version unless --sort is set. Fetch
+also supports all the filters from the filter command.
+If the --versions flag is set, the versions passed will
+be merged with the fetched versions.`,
 
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 			return utils.InitializeConfig(cmd)
@@ -41,41 +74,374 @@ fetched versions.`,
 		},
 	}
 	fetchCmd.Flags().StringVarP(&config.Owner, "owner", "o", "", "The owner of the registry or repository")
-	fetchCmd.Flags().StringVarP(&config.Repository, "repo", "r", "", "The repository or registry to fetch the Semver tags from")
+	fetchCmd.Flags().StringVarP(&config.Repository, "repo", "r", "", "The repository or registry to fetch the Semver tags from, or the file to read them from with the file platform (\"-\" reads stdin)")
 	fetchCmd.Flags().StringVarP(&config.Token, "token", "t", "", "The token to access the repository")
-	fetchCmd.Flags().StringVarP(&config.Platform, "platform", "p", "github", "The platform to fetch the Semver from, options: github")
+	fetchCmd.Flags().StringVar(&config.Username, "username", "", "The username paired with --token when a registry requires credentials (optional)")
+	fetchCmd.Flags().StringVarP(&config.Platform, "platform", "p", "github", "The platform to fetch the Semver from, options: github, gitlab, gitea, forgejo, bitbucket-server, oci, git, file, npm, goproxy, pypi, crates, maven, helm")
+	fetchCmd.Flags().StringVar(&config.BaseURL, "base-url", "", "The base URL of a self-hosted platform instance or custom registry e.g. https://gitlab.example.com (optional)")
+	fetchCmd.Flags().StringVar(&config.APIURL, "api-url", "", "The API URL of a GitHub Enterprise Server instance e.g. https://github.example.com/api/v3, defaults to $GITHUB_API_URL, github platform only (optional)")
+	fetchCmd.Flags().StringVar(&config.UploadURL, "upload-url", "", "The upload API URL of a GitHub Enterprise Server instance, derived from --api-url by default, github platform only (optional)")
+	fetchCmd.Flags().StringVar(&config.CABundle, "ca-bundle", "", "A PEM file of CA certificates to trust besides the system ones, e.g. for a self-hosted instance (optional)")
+	fetchCmd.Flags().StringVar(&config.Ref, "ref", "", "Only fetch the tags reachable from this git ref, git platform only (optional)")
+	fetchCmd.Flags().StringVar(&config.TagPrefix, "tag-prefix", "", "Only fetch the tags starting with this prefix and strip it e.g. v or app/, git platform only (optional)")
+	fetchCmd.Flags().StringVar(&config.Chart, "chart", "", "The chart to fetch the versions of from the Helm repository passed with --repo, helm platform only")
+	fetchCmd.Flags().BoolVar(&config.AppVersion, "app-version", false, "Fetch the appVersion values of the chart instead of its versions, helm platform only (optional)")
+	fetchCmd.Flags().StringVar(&config.Kind, "kind", "tags", "What the versions are read from, options: tags, releases (github platform only)")
+	fetchCmd.Flags().BoolVar(&config.NoDrafts, "exclude-drafts", false, "Exclude the draft releases, releases kind only")
+	fetchCmd.Flags().BoolVar(&config.NoYanked, "exclude-yanked", false, "Exclude the versions yanked from the registry, crates platform only")
+	fetchCmd.Flags().BoolVar(&config.DistTags, "dist-tags", false, "Annotate the versions with their dist-tags e.g. 1.3.0 # latest, npm platform only")
+	fetchCmd.Flags().BoolVar(&config.LatestOnly, "latest-only", false, "Only return the release marked latest, releases kind only")
+	fetchCmd.Flags().StringArrayVar(&config.Sources, "source", []string{}, "A platform:location source to fetch concurrently with the others and merge, e.g. git:. or oci:ghcr.io/org/app, replaces --owner and --repo (repeatable)")
+	fetchCmd.Flags().BoolVar(&config.NoCache, "no-cache", false, "Do not read nor store the fetched tags in the on-disk cache")
+	fetchCmd.Flags().DurationVar(&config.CacheTTL, "cache-ttl", 0, "How long cached tags are used without revalidating them, they are always revalidated with a conditional request when 0")
+	fetchCmd.Flags().StringVar(&config.CacheDir, "cache-dir", "", "The directory of the tag cache, defaults to smgr in the user cache directory (optional)")
+	fetchCmd.Flags().StringVar(&config.TokenType, "token-type", "", "The kind of token passed with --token, gitlab options: private, job (optional)")
+
+	fetchCmd.MarkFlagsMutuallyExclusive("api-url", "base-url")
 
 	return fetchCmd
 }
 
 func RunFetchSemverTags(config *config, cmd *cobra.Command, filterArgs *filter.FilterArgs) error {
+	if (config.NoDrafts || config.LatestOnly) && config.Kind != datasourceUtils.ReleasesKind {
+		return errors.New("--exclude-drafts and --latest-only require --kind releases")
+	}
+	if config.DistTags && (config.Platform != "npm" || len(config.Sources) > 0) {
+		return errors.New("--dist-tags requires the npm platform and cannot be combined with --source")
+	}
+	if (config.APIURL != "" || config.UploadURL != "") && config.Platform != "" && config.Platform != "github" {
+		return fmt.Errorf("--api-url and --upload-url require the github platform, use --base-url for %s", config.Platform)
+	}
+	namespace, err := models.ParseNamespace(filterArgs.Namespace)
+	if err != nil {
+		return err
+	}
+	config.namespace = namespace
+	prefixStyle, err := models.ParsePrefixStyle(filterArgs.PrefixStyle)
+	if err != nil {
+		return err
+	}
+	config.prefix = prefixStyle
+	if len(config.Sources) > 0 {
+		if config.Kind == datasourceUtils.ReleasesKind {
+			return errors.New("--kind releases cannot be combined with --source")
+		}
+		return runFetchSources(config, cmd, filterArgs)
+	}
 
-	datasource := newDatasource(config.dryRun, config.Platform, config.Token)
+	fetcher, err := newFetcher(config)
+	if err != nil {
+		return err
+	}
+	if fileFetcher, ok := fetcher.(*file.FileClient); ok {
+		fileFetcher.Stdin = cmd.InOrStdin()
+	}
 
 	klog.V(1).Info("Fetching tags...")
-	semverTags, err := datasource.FetchSemverTags(config.Owner, config.Repository)
+	semverTags, err := fetcher.FetchTags()
 	if err != nil {
 		return err
 	}
+	utils.ReportWarnings(cmd, fetcher)
 	klog.V(1).Infof("Fetched %d tags", len(semverTags))
-	filterArgs.Versions = strings.Join(semverTags, " ")
-	filteredTags, err := filter.Filter(filterArgs)
+	if config.NoYanked {
+		semverTags, err = excludeYanked(semverTags, utils.YankedVersions(fetcher))
+		if err != nil {
+			return err
+		}
+	}
+
+	lister, isReleaseLister := fetcher.(fetch.ReleaseLister)
+	if config.Kind != datasourceUtils.ReleasesKind || !isReleaseLister {
+		fetched := filterPkg.ParseResult{Versions: semverTags, Errors: utils.EntryErrors(fetcher)}
+		filteredTags, err := mergeAndFilter(config, cmd, fetched, filterArgs)
+		if err != nil {
+			return err
+		}
+		if distTagLister, ok := fetcher.(fetch.DistTagLister); ok && config.DistTags {
+			printAnnotated(config, cmd, filteredTags, distTagAnnotations(distTagLister.DistTags()))
+			return nil
+		}
+		cmd.Println(config.prefix.ApplyAll(filteredTags).String())
+		return nil
+	}
+
+	return printReleases(config, cmd, lister.Releases(), filterArgs)
+}
+
+// printReleases filters the fetched releases and prints every version with
+// its release flags and publish date
+func printReleases(config *config, cmd *cobra.Command, releases []datasourceUtils.Release, filterArgs *filter.FilterArgs) error {
+	semverTags := []models.Version{}
+	annotations := map[string]string{}
+	for _, release := range releases {
+		if config.NoDrafts && release.Draft || config.LatestOnly && !release.Latest {
+			continue
+		}
+		semverTags = append(semverTags, release.Version)
+		annotations[release.Version.String()] = release.Annotation()
+	}
+
+	filteredTags, err := mergeAndFilter(config, cmd, filterPkg.ParseResult{Versions: semverTags}, filterArgs)
 	if err != nil {
 		return err
 	}
-	cmd.Println(filteredTags.String())
+	printAnnotated(config, cmd, filteredTags, annotations)
 
 	return nil
 }
 
-func newDatasource(dryRun bool, platform, token string) datasourceUtils.Datasource {
+// printAnnotated prints every version on its own line, followed by its
+// annotation when it has one
+func printAnnotated(config *config, cmd *cobra.Command, versions models.VersionSlice, annotations map[string]string) {
+	for _, version := range versions {
+		styled := config.prefix.Apply(version)
+		if annotation := annotations[version.String()]; annotation != "" {
+			cmd.Printf("%s # %s\n", styled.String(), annotation)
+			continue
+		}
+		cmd.Println(styled.String())
+	}
+}
+
+// distTagAnnotations returns the dist-tags of every tagged version, e.g.
+// "latest" or "beta, next"
+func distTagAnnotations(distTags map[string]models.Version) map[string]string {
+	tags := map[string][]string{}
+	for tag, version := range distTags {
+		tags[version.String()] = append(tags[version.String()], tag)
+	}
+
+	annotations := map[string]string{}
+	for version, versionTags := range tags {
+		sort.Strings(versionTags)
+		annotations[version] = strings.Join(versionTags, ", ")
+	}
+	return annotations
+}
+
+// runFetchSources fetches the --source datasources concurrently and prints
+// every filtered version with the sources it was found in
+func runFetchSources(config *config, cmd *cobra.Command, filterArgs *filter.FilterArgs) error {
+	sources := []fetch.Source{}
+	stdinSource := ""
+	if filterArgs.VersionsFile == file.Stdin {
+		stdinSource = "--versions-file " + file.Stdin
+	}
+	for _, rawSource := range config.Sources {
+		sourceConfig, err := fetch.ParseSource(rawSource)
+		if err != nil {
+			return err
+		}
+		if sourceConfig.Platform == "file" && sourceConfig.Repository == file.Stdin {
+			// concurrent sources would split the lines of stdin between them
+			if stdinSource != "" {
+				return fmt.Errorf("%s: stdin can only be read once, it is already read by %s", rawSource, stdinSource)
+			}
+			stdinSource = rawSource
+		}
+		fetcher, err := newSourceFetcher(config, sourceConfig)
+		if err != nil {
+			return fmt.Errorf("%s: %w", rawSource, err)
+		}
+		if fileFetcher, ok := fetcher.(*file.FileClient); ok {
+			fileFetcher.Stdin = cmd.InOrStdin()
+		}
+		sources = append(sources, fetch.Source{Name: rawSource, Fetcher: fetcher})
+	}
+
+	klog.V(1).Infof("Fetching tags from %d sources...", len(sources))
+	sourcedVersions, err := fetch.FetchAll(sources)
+	if err != nil {
+		return err
+	}
+	fetched := filterPkg.ParseResult{Versions: []models.Version{}, Errors: []error{}}
+	yanked := []models.Version{}
+	for _, source := range sources {
+		utils.ReportWarnings(cmd, source.Fetcher)
+		fetched.Errors = append(fetched.Errors, utils.EntryErrors(source.Fetcher)...)
+		yanked = append(yanked, utils.YankedVersions(source.Fetcher)...)
+	}
+	klog.V(1).Infof("Fetched %d distinct tags", len(sourcedVersions))
+
+	versionSources := map[string][]string{}
+	for _, sourcedVersion := range sourcedVersions {
+		fetched.Versions = append(fetched.Versions, sourcedVersion.Version)
+		versionSources[sourcedVersion.String()] = sourcedVersion.Sources
+	}
+	if config.NoYanked {
+		fetched.Versions, err = excludeYanked(fetched.Versions, yanked)
+		if err != nil {
+			return err
+		}
+	}
+
+	filteredTags, err := mergeAndFilter(config, cmd, fetched, filterArgs)
+	if err != nil {
+		return err
+	}
+	annotations := map[string]string{}
+	for version, sources := range versionSources {
+		annotations[version] = strings.Join(sources, ", ")
+	}
+	printAnnotated(config, cmd, filteredTags, annotations)
+
+	return nil
+}
+
+// mergeAndFilter merges the --versions and --versions-file versions with the
+// fetched ones, checks the invalid entries of the input, sorts the versions
+// and applies the filter flags
+func mergeAndFilter(config *config, cmd *cobra.Command, fetched filterPkg.ParseResult, filterArgs *filter.FilterArgs) (models.VersionSlice, error) {
+	input := filterPkg.ParseResult{}
+	input.Append(fetched)
+	input.Append(utils.ParseVersions(filterArgs.Versions, config.namespace))
+	if filterArgs.VersionsFile != "" {
+		fileInput, err := utils.ReadVersionsFile(cmd, filterArgs.VersionsFile, config.namespace)
+		if err != nil {
+			return nil, err
+		}
+		input.Append(fileInput)
+	}
+	if err := utils.CheckInput(cmd, input.Errors, filterArgs.StrictInput); err != nil {
+		return nil, err
+	}
+
+	versions := models.VersionSlice(input.Versions)
+	sort.Stable(versions)
+
+	return filter.FilterVersions(versions, filterArgs)
+}
+
+// excludeYanked drops the yanked versions from the fetched ones, build
+// metadata included
+func excludeYanked(versions []models.Version, yanked []models.Version) ([]models.Version, error) {
+	patterns := []models.VersionPattern{}
+	for _, version := range yanked {
+		version.Prefix = ""
+		pattern, err := models.ParseVersionPattern(version.String())
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, pattern)
+	}
+	return filterPkg.ApplyFilters(versions, filterPkg.ExcludePatternFilter(patterns...))
+}
+
+func newFetcher(config *config) (fetch.Fetcher, error) {
+	platform := config.Platform
 	if len(platform) == 0 {
 		platform = "github"
 	}
-	if dryRun {
+	if config.dryRun {
 		platform = "dry-run"
 	}
-	semverSvc := datasourceUtils.NewSemverSvc(platform, token)
 
-	return semverSvc
+	datasourceConfig := &sharedUtils.DatasourceConfig{
+		Owner:      config.Owner,
+		Repository: config.Repository,
+		Username:   config.Username,
+		Token:      config.Token,
+		Platform:   platform,
+		BaseURL:    config.baseURL(),
+		UploadURL:  config.UploadURL,
+		TokenType:  config.TokenType,
+		Ref:        config.Ref,
+		TagPrefix:  config.TagPrefix,
+		Namespace:  config.namespace,
+		Chart:      config.Chart,
+		AppVersion: config.AppVersion,
+		Kind:       config.Kind,
+	}
+	if err := withTransport(config, datasourceConfig); err != nil {
+		return nil, err
+	}
+
+	return fetch.NewFetcher(datasourceConfig)
+}
+
+// newSourceFetcher returns the Fetcher of a --source datasource. The
+// credentials and base URL flags only apply to the sources of the --platform
+// platform, the other settings are platform specific and apply to all.
+func newSourceFetcher(config *config, sourceConfig *sharedUtils.DatasourceConfig) (fetch.Fetcher, error) {
+	platform := config.Platform
+	if len(platform) == 0 {
+		platform = "github"
+	}
+	if sourceConfig.Platform == platform {
+		sourceConfig.Username = config.Username
+		sourceConfig.Token = config.Token
+		sourceConfig.BaseURL = config.baseURL()
+		sourceConfig.UploadURL = config.UploadURL
+		sourceConfig.TokenType = config.TokenType
+	}
+	sourceConfig.Ref = config.Ref
+	sourceConfig.TagPrefix = config.TagPrefix
+	sourceConfig.Namespace = config.namespace
+	sourceConfig.AppVersion = config.AppVersion
+	if sourceConfig.Chart == "" {
+		sourceConfig.Chart = config.Chart
+	}
+	if config.dryRun {
+		sourceConfig.Platform = "dry-run"
+	}
+	if err := withTransport(config, sourceConfig); err != nil {
+		return nil, err
+	}
+
+	return fetch.NewFetcher(sourceConfig)
+}
+
+// baseURL returns the --api-url of GitHub Enterprise Server or the --base-url
+func (c *config) baseURL() string {
+	if c.APIURL != "" {
+		return c.APIURL
+	}
+	return c.BaseURL
+}
+
+// withTransport sends the requests of an HTTP datasource through the tag
+// cache, unless --no-cache is set, trusting the --ca-bundle certificates
+func withTransport(config *config, datasourceConfig *sharedUtils.DatasourceConfig) error {
+	switch datasourceConfig.Platform {
+	case "git", "file", "dry-run":
+		return nil
+	}
+
+	var transport http.RoundTripper
+	if config.CABundle != "" {
+		caTransport, err := datasourceUtils.NewCATransport(config.CABundle)
+		if err != nil {
+			return err
+		}
+		transport = caTransport
+	}
+	datasourceConfig.Transport = transport
+	if config.NoCache {
+		return nil
+	}
+
+	dir := config.CacheDir
+	if dir == "" {
+		defaultDir, err := cache.DefaultDir()
+		if err != nil {
+			klog.V(1).Infof("Tag cache disabled: %v", err)
+			return nil
+		}
+		dir = defaultDir
+	}
+
+	token := datasourceConfig.Token
+	if datasourceConfig.Platform == "npm" {
+		// without --token the packument is read with the .npmrc token
+		token = npm.Token(datasourceConfig)
+	}
+	key := cache.Key{
+		Platform:   datasourceConfig.Platform,
+		Owner:      datasourceConfig.Owner,
+		Repository: datasourceConfig.Repository,
+		Credential: cache.CredentialHash(datasourceConfig.Username, token),
+	}
+	datasourceConfig.Transport = cache.New(dir, config.CacheTTL).Transport(key, transport)
+	return nil
 }
@@ -2,9 +2,14 @@ package fetch
 
 import (
 	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"src/cmd/smgr/cmd/filter"
 
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/joho/godotenv"
@@ -45,7 +50,7 @@ func TestNewFetchCommand(t *testing.T) {
 		filterCmd := filter.NewFilterCommand(filterArgs)
 		cmd.Flags().AddFlagSet(filterCmd.Flags())
 		flags := cmd.Flags()
-		expectedFlags := []string{"owner", "repo", "token", "platform", "highest"}
+		expectedFlags := []string{"owner", "repo", "token", "username", "platform", "base-url", "api-url", "upload-url", "ca-bundle", "token-type", "ref", "tag-prefix", "chart", "app-version", "source", "kind", "exclude-drafts", "exclude-yanked", "dist-tags", "latest-only", "no-cache", "cache-ttl", "cache-dir", "highest", "lowest", "sort", "limit", "dedupe", "group-by", "per-group", "exclude", "range", "release-only", "prerelease-only", "namespace", "prefix-style", "strict-input"}
 		for _, expectedFlag := range expectedFlags {
 			if flags.Lookup(expectedFlag) == nil {
 				t.Errorf("Command does not have expected flag '%s'", expectedFlag)
@@ -128,3 +133,496 @@ func TestNewFetchCommandRealRepo(t *testing.T) {
 		})
 	}
 }
+
+func TestNewFetchCommandSources(t *testing.T) {
+	dir := t.TempDir()
+	tagsPath := filepath.Join(dir, "tags.txt")
+	publishedPath := filepath.Join(dir, "published.txt")
+	assert.NoError(t, os.WriteFile(tagsPath, []byte("1.0.0\n1.1.0\n1.2.0-rc.1\n"), 0o644))
+	assert.NoError(t, os.WriteFile(publishedPath, []byte("0.9.0\n1.0.0\n1.1.0+build.7\n"), 0o644))
+	monorepoPath := filepath.Join(dir, "monorepo.txt")
+	assert.NoError(t, os.WriteFile(monorepoPath, []byte("service-a/v1.0.0\nservice-b/v2.0.0\nservice-a/v1.1.0\n"), 0o644))
+	invalidPath := filepath.Join(dir, "invalid.txt")
+	assert.NoError(t, os.WriteFile(invalidPath, []byte("1.0.0\nbad.version\n"), 0o644))
+	tagsSource, publishedSource, monorepoSource := "file:"+tagsPath, "file:"+publishedPath, "file:"+monorepoPath
+
+	tests := []struct {
+		name           string
+		args           []string
+		stdin          string
+		expectedOutput string
+		wantErr        bool
+	}{
+		{
+			name:           "Merged versions with their sources",
+			args:           []string{"--source", tagsSource, "--source", publishedSource},
+			expectedOutput: "0.9.0 # " + publishedSource + "\n1.0.0 # " + tagsSource + ", " + publishedSource + "\n1.1.0 # " + tagsSource + ", " + publishedSource + "\n1.2.0-rc.1 # " + tagsSource + "\n",
+		},
+		{
+			name:           "Filters apply to the merged versions",
+			args:           []string{"--source", tagsSource, "--source", publishedSource, "--stream", "*.*.*", "--highest"},
+			expectedOutput: "1.1.0 # " + tagsSource + ", " + publishedSource + "\n",
+		},
+		{
+			name:           "Sorted and limited versions",
+			args:           []string{"--source", tagsSource, "--sort", "desc", "--limit", "2"},
+			expectedOutput: "1.2.0-rc.1 # " + tagsSource + "\n1.1.0 # " + tagsSource + "\n",
+		},
+		{
+			name:           "Namespaced tags",
+			args:           []string{"--source", monorepoSource, "--namespace", "service-a/v"},
+			expectedOutput: "1.0.0 # " + monorepoSource + "\n1.1.0 # " + monorepoSource + "\n",
+		},
+		{
+			name:    "Invalid namespace",
+			args:    []string{"--source", monorepoSource, "--namespace", "{version}/{version}"},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid entries of a source fail with strict input",
+			args:    []string{"--source", "file:" + invalidPath, "--strict-input"},
+			wantErr: true,
+		},
+		{
+			name:           "Stdin source",
+			args:           []string{"--source", "file:-", "--source", tagsSource, "--highest"},
+			stdin:          "1.3.0\n",
+			expectedOutput: "1.3.0 # file:-\n",
+		},
+		{
+			name:    "Stdin read by two sources",
+			args:    []string{"--source", "file:-", "--source", "file:-"},
+			stdin:   "1.3.0\n1.4.0\n",
+			wantErr: true,
+		},
+		{
+			name:    "Stdin read by a source and the versions file",
+			args:    []string{"--source", "file:-", "--versions-file", "-"},
+			stdin:   "1.3.0\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid source",
+			args:    []string{"--source", tagsPath},
+			wantErr: true,
+		},
+		{
+			name:    "Failed source",
+			args:    []string{"--source", tagsSource, "--source", "file:" + filepath.Join(dir, "missing.txt")},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := new(bytes.Buffer)
+			filterArgs := &filter.FilterArgs{}
+			filterCmd := filter.NewFilterCommand(filterArgs)
+
+			cmd := NewFetchCommand(filterArgs)
+			cmd.Flags().AddFlagSet(filterCmd.Flags())
+			cmd.SetOut(output)
+			cmd.SetErr(output)
+			cmd.SetIn(strings.NewReader(tc.stdin))
+			cmd.SetArgs(tc.args)
+
+			err := cmd.Execute()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+
+	t.Run("Invalid entries of a source are ignored", func(t *testing.T) {
+		output := new(bytes.Buffer)
+		errOutput := new(bytes.Buffer)
+		filterArgs := &filter.FilterArgs{}
+		filterCmd := filter.NewFilterCommand(filterArgs)
+
+		cmd := NewFetchCommand(filterArgs)
+		cmd.Flags().AddFlagSet(filterCmd.Flags())
+		cmd.SetOut(output)
+		cmd.SetErr(errOutput)
+		cmd.SetArgs([]string{"--source", "file:" + invalidPath})
+
+		assert.NoError(t, cmd.Execute())
+		assert.Equal(t, "1.0.0 # file:"+invalidPath+"\n", output.String())
+		assert.Contains(t, errOutput.String(), `invalid version "bad.version"`)
+		assert.Contains(t, errOutput.String(), "warning: ignored 1 invalid versions")
+	})
+}
+
+func TestNewFetchCommandCache(t *testing.T) {
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `[{"name":"1.0.0"},{"name":"1.1.0"}]`)
+	}))
+	defer server.Close()
+	cacheDir := t.TempDir()
+
+	run := func(extraArgs ...string) string {
+		output := new(bytes.Buffer)
+		filterArgs := &filter.FilterArgs{}
+		filterCmd := filter.NewFilterCommand(filterArgs)
+
+		cmd := NewFetchCommand(filterArgs)
+		cmd.Flags().AddFlagSet(filterCmd.Flags())
+		cmd.SetOut(output)
+		cmd.SetErr(output)
+		cmd.SetArgs(append([]string{"-p", "gitea", "--base-url", server.URL, "-o", "org", "-r", "app", "--cache-dir", cacheDir}, extraArgs...))
+		assert.NoError(t, cmd.Execute())
+		return output.String()
+	}
+
+	assert.Equal(t, "1.0.0 1.1.0\n", run())
+	assert.Equal(t, "1.0.0 1.1.0\n", run())
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, notModified)
+
+	assert.Equal(t, "1.0.0 1.1.0\n", run("--no-cache"))
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, 1, notModified)
+
+	assert.Equal(t, "1.0.0 1.1.0\n", run("--cache-ttl", "1h"))
+	assert.Equal(t, 3, requests)
+}
+
+func TestNewFetchCommandCacheNpmrcToken(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("Authorization") != "Bearer npmrc-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprint(w, `{"name":"app","versions":{"1.0.0":{}}}`)
+	}))
+	defer server.Close()
+	cacheDir := t.TempDir()
+	npmrcPath := filepath.Join(t.TempDir(), ".npmrc")
+	registry := strings.TrimPrefix(server.URL, "http:")
+	assert.NoError(t, os.WriteFile(npmrcPath, []byte(registry+"/:_authToken=npmrc-token\n"), 0o600))
+
+	run := func() error {
+		filterArgs := &filter.FilterArgs{}
+		filterCmd := filter.NewFilterCommand(filterArgs)
+
+		cmd := NewFetchCommand(filterArgs)
+		cmd.Flags().AddFlagSet(filterCmd.Flags())
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs([]string{"-p", "npm", "--base-url", server.URL, "-r", "app", "--cache-dir", cacheDir, "--cache-ttl", "1h"})
+		return cmd.Execute()
+	}
+
+	t.Setenv("NPM_CONFIG_USERCONFIG", npmrcPath)
+	assert.NoError(t, run())
+	assert.Equal(t, 1, requests)
+
+	// The packument read with the .npmrc token is not served without it
+	t.Setenv("NPM_CONFIG_USERCONFIG", filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, run())
+	assert.Equal(t, 2, requests)
+}
+
+func TestNewFetchCommandPrefixStyle(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "v1.2.3\nv1.1.0\n")
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+	}{
+		{
+			name:           "Go module versions keep their v prefix",
+			args:           []string{"--prefix-style", "keep"},
+			expectedOutput: "v1.2.3\n",
+		},
+		{
+			name:           "Go module versions without their v prefix",
+			args:           []string{"--prefix-style", "strip"},
+			expectedOutput: "1.2.3\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := new(bytes.Buffer)
+			filterArgs := &filter.FilterArgs{}
+			filterCmd := filter.NewFilterCommand(filterArgs)
+
+			cmd := NewFetchCommand(filterArgs)
+			cmd.Flags().AddFlagSet(filterCmd.Flags())
+			cmd.SetOut(output)
+			cmd.SetErr(output)
+			cmd.SetArgs(append([]string{"-p", "goproxy", "--base-url", server.URL, "-r", "github.com/org/module", "--no-cache", "--highest"}, tc.args...))
+
+			assert.NoError(t, cmd.Execute())
+			assert.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+}
+
+func TestNewFetchCommandReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/owner/repo/releases":
+			fmt.Fprint(w, `[
+				{"id":3,"tag_name":"1.2.0","draft":true},
+				{"id":2,"tag_name":"1.2.0-rc.1","prerelease":true,"published_at":"2024-05-02T10:00:00Z"},
+				{"id":1,"tag_name":"1.1.0","published_at":"2024-05-01T10:00:00Z"}
+			]`)
+		case "/api/v3/repos/owner/repo/releases/latest":
+			fmt.Fprint(w, `{"id":1,"tag_name":"1.1.0"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		args           []string
+		platform       string
+		expectedOutput string
+		wantErr        bool
+	}{
+		{
+			name:           "Releases with their flags",
+			args:           []string{"--kind", "releases"},
+			expectedOutput: "1.1.0 # latest, published 2024-05-01\n1.2.0-rc.1 # prerelease, published 2024-05-02\n1.2.0 # draft\n",
+		},
+		{
+			name:           "Drafts excluded",
+			args:           []string{"--kind", "releases", "--exclude-drafts", "--highest"},
+			expectedOutput: "1.2.0-rc.1 # prerelease, published 2024-05-02\n",
+		},
+		{
+			name:           "Latest only",
+			args:           []string{"--kind", "releases", "--latest-only"},
+			expectedOutput: "1.1.0 # latest, published 2024-05-01\n",
+		},
+		{
+			name:    "Release filters without the releases kind",
+			args:    []string{"--latest-only"},
+			wantErr: true,
+		},
+		{
+			name:     "Releases kind on another platform",
+			args:     []string{"--kind", "releases"},
+			platform: "gitea",
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := new(bytes.Buffer)
+			filterArgs := &filter.FilterArgs{}
+			filterCmd := filter.NewFilterCommand(filterArgs)
+
+			cmd := NewFetchCommand(filterArgs)
+			cmd.Flags().AddFlagSet(filterCmd.Flags())
+			cmd.SetOut(output)
+			cmd.SetErr(output)
+			urlArgs := []string{"--api-url", server.URL}
+			if tc.platform != "" {
+				urlArgs = []string{"-p", tc.platform, "--base-url", server.URL}
+			}
+			cmd.SetArgs(append(append(urlArgs, "-o", "owner", "-r", "repo", "--no-cache"), tc.args...))
+
+			err := cmd.Execute()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+}
+
+func TestNewFetchCommandAPIURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/owner/repo/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, `[{"name":"1.0.0"},{"name":"1.1.0"}]`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		wantErr        bool
+	}{
+		{
+			name:           "API URL of the github platform",
+			args:           []string{"--api-url", server.URL + "/api/v3"},
+			expectedOutput: "1.0.0 1.1.0\n",
+		},
+		{
+			name:    "API URL on another platform",
+			args:    []string{"-p", "gitlab", "--api-url", server.URL + "/api/v3"},
+			wantErr: true,
+		},
+		{
+			name:    "Upload URL on another platform",
+			args:    []string{"-p", "gitea", "--base-url", server.URL, "--upload-url", server.URL + "/api/uploads"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := new(bytes.Buffer)
+			filterArgs := &filter.FilterArgs{}
+			filterCmd := filter.NewFilterCommand(filterArgs)
+
+			cmd := NewFetchCommand(filterArgs)
+			cmd.Flags().AddFlagSet(filterCmd.Flags())
+			cmd.SetOut(output)
+			cmd.SetErr(output)
+			cmd.SetArgs(append([]string{"-o", "owner", "-r", "repo", "--no-cache"}, tc.args...))
+
+			err := cmd.Execute()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+}
+
+func TestNewFetchCommandYanked(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/se/rd/serde" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, `{"name":"serde","vers":"1.0.0","yanked":false}`)
+		fmt.Fprintln(w, `{"name":"serde","vers":"1.0.1","yanked":true}`)
+		fmt.Fprintln(w, `{"name":"serde","vers":"1.1.0-rc.1","yanked":true}`)
+		fmt.Fprintln(w, `{"name":"serde","vers":"1.1.0","yanked":false}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+	}{
+		{
+			name:           "Yanked versions are kept by default",
+			args:           []string{"-p", "crates", "-r", "serde"},
+			expectedOutput: "1.0.0 1.0.1 1.1.0-rc.1 1.1.0\n",
+		},
+		{
+			name:           "Yanked versions excluded",
+			args:           []string{"-p", "crates", "-r", "serde", "--exclude-yanked"},
+			expectedOutput: "1.0.0 1.1.0\n",
+		},
+		{
+			name:           "Yanked versions excluded from a source",
+			args:           []string{"-p", "crates", "--source", "crates:serde", "--exclude-yanked"},
+			expectedOutput: "1.0.0 # crates:serde\n1.1.0 # crates:serde\n",
+		},
+		{
+			name:           "Yanked and excluded versions",
+			args:           []string{"-p", "crates", "-r", "serde", "--exclude-yanked", "--exclude", "1.0.*"},
+			expectedOutput: "1.1.0\n",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := new(bytes.Buffer)
+			filterArgs := &filter.FilterArgs{}
+			filterCmd := filter.NewFilterCommand(filterArgs)
+
+			cmd := NewFetchCommand(filterArgs)
+			cmd.Flags().AddFlagSet(filterCmd.Flags())
+			cmd.SetOut(output)
+			cmd.SetErr(output)
+			cmd.SetArgs(append([]string{"--base-url", server.URL, "--no-cache"}, tc.args...))
+
+			assert.NoError(t, cmd.Execute())
+			assert.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+}
+
+func TestNewFetchCommandDistTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"app","dist-tags":{"latest":"2.0.0","stable":"2.0.0","next":"3.0.0-beta.1"},"versions":{"1.0.0":{},"2.0.0":{},"3.0.0-beta.1":{}}}`)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name           string
+		args           []string
+		expectedOutput string
+		wantErr        bool
+	}{
+		{
+			name:           "Versions without their dist-tags",
+			args:           []string{"-p", "npm"},
+			expectedOutput: "1.0.0 2.0.0 3.0.0-beta.1\n",
+		},
+		{
+			name:           "Versions annotated with their dist-tags",
+			args:           []string{"-p", "npm", "--dist-tags"},
+			expectedOutput: "1.0.0\n2.0.0 # latest, stable\n3.0.0-beta.1 # next\n",
+		},
+		{
+			name:           "Highest version with its dist-tags",
+			args:           []string{"-p", "npm", "--dist-tags", "--release-only", "--highest"},
+			expectedOutput: "2.0.0 # latest, stable\n",
+		},
+		{
+			name:    "Dist-tags on another platform",
+			args:    []string{"-p", "gitea", "--dist-tags"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			output := new(bytes.Buffer)
+			filterArgs := &filter.FilterArgs{}
+			filterCmd := filter.NewFilterCommand(filterArgs)
+
+			cmd := NewFetchCommand(filterArgs)
+			cmd.Flags().AddFlagSet(filterCmd.Flags())
+			cmd.SetOut(output)
+			cmd.SetErr(output)
+			cmd.SetArgs(append([]string{"--base-url", server.URL, "-r", "app", "--no-cache"}, tc.args...))
+
+			err := cmd.Execute()
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, output.String())
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"src/cmd/smgr/cmd/utils"
 	"src/cmd/smgr/models"
 	"src/cmd/smgr/pkg/filter"
 
@@ -13,25 +14,76 @@ func NewFilterCommand(filterArgs *FilterArgs) *cobra.Command {
 		Short: "Filter is a CLI tool for filtering versions",
 		Long:  `Filter is a CLI tool for filtering versions using various criteria.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			prefixStyle, err := models.ParsePrefixStyle(filterArgs.PrefixStyle)
+			if err != nil {
+				return err
+			}
+			input, err := InputVersions(cmd, filterArgs)
+			if err != nil {
+				return err
+			}
+			if err := utils.CheckInput(cmd, input.Errors, filterArgs.StrictInput); err != nil {
+				return err
+			}
 
-			semverTags, err := Filter(filterArgs)
+			semverTags, err := FilterVersions(input.Versions, filterArgs)
 			if err != nil {
 				return err
 			}
-			cmd.Println(semverTags.String())
+			cmd.Println(prefixStyle.ApplyAll(semverTags).String())
 			return nil
 		},
 	}
 
 	filterCmd.Flags().StringVarP(&filterArgs.Versions, "versions", "V", "", "Version list to filter")
+	filterCmd.Flags().StringVarP(&filterArgs.VersionsFile, "versions-file", "f", "", "File of newline, comma or space separated versions to filter, \"-\" reads stdin (optional)")
 	filterCmd.Flags().StringVarP(&filterArgs.StreamFilter, "stream", "s", "", "Filter by major, minor, patch, prerelease version and build metadata streams")
+	filterCmd.Flags().StringArrayVar(&filterArgs.Exclude, "exclude", []string{}, "Drop the versions matching a pattern of the --stream syntax e.g. 2.0.* or *.*.*-nightly.* (repeatable)")
+	filterCmd.Flags().StringVar(&filterArgs.Range, "range", "", "Filter by a range of versions in the npm syntax e.g. \">=1.0.0 <2.0.0\", ^1.2, ~1.2.3, 1.0.0 - 1.4.0 or \"^1 || ^2\"")
 	filterCmd.Flags().BoolVarP(&filterArgs.Highest, "highest", "H", false, "Filter by highest version")
+	filterCmd.Flags().BoolVar(&filterArgs.Lowest, "lowest", false, "Filter by lowest version")
+	filterCmd.Flags().StringVar(&filterArgs.Sort, "sort", "", "Sort the versions by Semver precedence, options: asc, desc (defaults to the input order, ascending for fetch)")
+	filterCmd.Flags().IntVar(&filterArgs.Limit, "limit", 0, "Only print the first N versions, after sorting (optional)")
+	filterCmd.Flags().StringVar(&filterArgs.Dedupe, "dedupe", string(filter.DedupeAll), "Which of the versions only differing by build metadata are kept, options: first, last, all")
+	filterCmd.Flags().StringVar(&filterArgs.GroupBy, "group-by", "", "Keep the highest version of every group, options: major, minor, prerelease-label (optional)")
+	filterCmd.Flags().IntVar(&filterArgs.PerGroup, "per-group", 1, "The number of highest versions kept per group with --group-by")
+	filterCmd.Flags().BoolVar(&filterArgs.Release, "release-only", false, "Filter out the prerelease versions")
+	filterCmd.Flags().BoolVar(&filterArgs.Prerelease, "prerelease-only", false, "Filter out the release versions")
+	filterCmd.Flags().BoolVar(&filterArgs.StrictInput, "strict-input", false, "Fail on invalid input versions instead of ignoring them with a warning")
+	filterCmd.Flags().StringVar(&filterArgs.PrefixStyle, "prefix-style", string(models.PrefixKeep), "How the v prefix of the versions is printed, options: keep, strip, add")
+	filterCmd.Flags().StringVar(&filterArgs.Namespace, "namespace", "", "Only read the versions of a monorepo component and strip its tag template e.g. service-a/v or lib/core@{version} (optional)")
+	filterCmd.MarkFlagsMutuallyExclusive("release-only", "prerelease-only")
+	filterCmd.MarkFlagsMutuallyExclusive("highest", "lowest")
 	return filterCmd
 }
 
+// InputVersions returns the versions passed with --versions and --versions-file
+// with the errors of their invalid entries, stdin is only read with
+// --versions-file -. The --namespace template is stripped from the versions
+// of the namespace.
+func InputVersions(cmd *cobra.Command, filterArgs *FilterArgs) (filter.ParseResult, error) {
+	namespace, err := models.ParseNamespace(filterArgs.Namespace)
+	if err != nil {
+		return filter.ParseResult{}, err
+	}
+	input := utils.ParseVersions(filterArgs.Versions, namespace)
+	if filterArgs.VersionsFile != "" {
+		fileInput, err := utils.ReadVersionsFile(cmd, filterArgs.VersionsFile, namespace)
+		if err != nil {
+			return filter.ParseResult{}, err
+		}
+		input.Append(fileInput)
+	}
+	return input, nil
+}
+
 func Filter(filterArgs *FilterArgs) (models.VersionSlice, error) {
 	versions := filter.GetValidVersions(filterArgs.Versions)
+	return FilterVersions(versions, filterArgs)
+}
 
+// FilterVersions applies the filters described by filterArgs to versions
+func FilterVersions(versions []models.Version, filterArgs *FilterArgs) (models.VersionSlice, error) {
 	filters := []filter.FilterFunc{}
 	if filterArgs.StreamFilter != "" {
 		pattern, err := models.ParseVersionPattern(filterArgs.StreamFilter)
@@ -41,9 +93,63 @@ func Filter(filterArgs *FilterArgs) (models.VersionSlice, error) {
 		filters = append(filters, filter.VersionPatternFilter(pattern))
 	}
 
+	if len(filterArgs.Exclude) > 0 {
+		patterns, err := models.ParseVersionPatterns(filterArgs.Exclude)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter.ExcludePatternFilter(patterns...))
+	}
+
+	if filterArgs.Range != "" {
+		constraint, err := models.ParseConstraint(filterArgs.Range)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter.ConstraintFilter(constraint))
+	}
+
+	if filterArgs.Release {
+		filters = append(filters, filter.ReleaseOnly())
+	}
+	if filterArgs.Prerelease {
+		filters = append(filters, filter.PrereleaseOnly())
+	}
+
+	if filterArgs.Dedupe != "" {
+		policy, err := filter.ParseDedupePolicy(filterArgs.Dedupe)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter.Dedupe(policy))
+	}
+
+	if filterArgs.GroupBy != "" {
+		grouping, err := filter.ParseGrouping(filterArgs.GroupBy)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter.HighestPerGroup(grouping, filterArgs.PerGroup))
+	}
+
 	if filterArgs.Highest {
 		filters = append(filters, filter.Highest())
 	}
+	if filterArgs.Lowest {
+		filters = append(filters, filter.Lowest())
+	}
+
+	if filterArgs.Sort != "" {
+		order, err := filter.ParseOrder(filterArgs.Sort)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, filter.Sort(order))
+	}
+
+	if filterArgs.Limit != 0 {
+		filters = append(filters, filter.Limit(filterArgs.Limit))
+	}
 
 	semverTags, err := filter.ApplyFilters(versions, filters...)
 	if err != nil {
@@ -2,7 +2,20 @@ package filter
 
 type FilterArgs struct {
 	StreamFilter string
+	Exclude      []string
+	Range        string
 	Highest      bool
+	Lowest       bool
+	Sort         string
+	Limit        int
+	Dedupe       string
+	GroupBy      string
+	PerGroup     int
 	Release      bool
+	Prerelease   bool
 	Versions     string
+	VersionsFile string
+	Namespace    string
+	PrefixStyle  string
+	StrictInput  bool
 }
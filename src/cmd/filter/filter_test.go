@@ -2,6 +2,9 @@ package filter
 
 import (
 	"bytes"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -28,10 +31,100 @@ func TestNewFilterCommand(t *testing.T) {
 			expectedOut: "1.2.3",
 		},
 		{
-			name:        "Provided multiple versions highest with some bad versions",
-			inputArgs:   []string{"--versions", "1.2.3, 1.1.1, bad.version", "--highest"},
+			name:        "Provided v prefixed versions keep their prefix",
+			inputArgs:   []string{"--versions", "v1.2.3 1.1.1 v1.3.0-rc.1", "--stream", "1.*.*"},
+			expectedOut: "v1.2.3 1.1.1",
+		},
+		{
+			name:        "Provided v prefixed versions with stripped prefix",
+			inputArgs:   []string{"--versions", "v1.2.3, 1.1.1", "--highest", "--prefix-style", "strip"},
 			expectedOut: "1.2.3",
 		},
+		{
+			name:        "Provided versions with added prefix",
+			inputArgs:   []string{"--versions", "v1.2.3, 1.1.1", "--prefix-style", "add"},
+			expectedOut: "v1.2.3 v1.1.1",
+		},
+		{
+			name:        "Provided versions in a range",
+			inputArgs:   []string{"--versions", "0.9.0 1.0.0 1.4.2 1.5.0-rc.1 2.0.0", "--range", ">=1.0.0 <2.0.0"},
+			expectedOut: "1.0.0 1.4.2",
+		},
+		{
+			name:        "Provided versions highest in a caret range",
+			inputArgs:   []string{"--versions", "1.2.0 1.4.2 2.0.0", "--range", "^1.2", "--highest"},
+			expectedOut: "1.4.2",
+		},
+		{
+			name:        "Provided versions highest stable in a range",
+			inputArgs:   []string{"--versions", "1.9.0 2.0.0 2.3.0 2.4.0-rc.1 3.0.0", "--range", "2.x", "--release-only", "--highest"},
+			expectedOut: "2.3.0",
+		},
+		{
+			name:        "Provided versions prereleases only",
+			inputArgs:   []string{"--versions", "1.9.0 2.0.0-rc.1 2.0.0 2.4.0-rc.1", "--prerelease-only"},
+			expectedOut: "2.0.0-rc.1 2.4.0-rc.1",
+		},
+		{
+			name:        "Provided versions highest patch of every minor",
+			inputArgs:   []string{"--versions", "1.0.0 1.0.3 1.1.0 1.1.2 2.0.0-rc.1", "--group-by", "minor", "--release-only"},
+			expectedOut: "1.0.3 1.1.2",
+		},
+		{
+			name:        "Provided versions top 2 of every major",
+			inputArgs:   []string{"--versions", "1.0.0 1.0.3 1.1.0 2.0.0", "--group-by", "major", "--per-group", "2"},
+			expectedOut: "1.0.3 1.1.0 2.0.0",
+		},
+		{
+			name:        "Provided versions sorted by descending precedence",
+			inputArgs:   []string{"--versions", "1.0.0-rc.1 1.0.0 1.0.0-beta.11 0.9.0 1.0.0-beta.2", "--sort", "desc"},
+			expectedOut: "1.0.0 1.0.0-rc.1 1.0.0-beta.11 1.0.0-beta.2 0.9.0",
+		},
+		{
+			name:        "Provided versions three highest releases",
+			inputArgs:   []string{"--versions", "1.0.0 1.2.0 1.3.0-rc.1 0.9.0 1.1.0", "--release-only", "--sort", "desc", "--limit", "3"},
+			expectedOut: "1.2.0 1.1.0 1.0.0",
+		},
+		{
+			name:        "Provided versions lowest in a stream",
+			inputArgs:   []string{"--versions", "1.2.0 1.0.1 2.0.0 1.0.0-rc.1", "--stream", "1.*.*", "--lowest"},
+			expectedOut: "1.0.1",
+		},
+		{
+			name:        "Provided versions of a range of minors",
+			inputArgs:   []string{"--versions", "1.1.0 1.2.0 1.3.4 1.4.0 2.3.0", "--stream", "1.[2-3].x"},
+			expectedOut: "1.2.0 1.3.4",
+		},
+		{
+			name:        "Provided versions of any release candidate",
+			inputArgs:   []string{"--versions", "1.0.0-rc 1.0.0-rc.1 1.0.0-rc.1.1 1.0.0-beta.1", "--stream", "*.*.*-rc.**"},
+			expectedOut: "1.0.0-rc 1.0.0-rc.1 1.0.0-rc.1.1",
+		},
+		{
+			name:        "Provided versions of a stream with exclusions",
+			inputArgs:   []string{"--versions", "1.9.0 2.0.0 2.0.1 2.1.0 2.1.1 2.2.0-nightly.1", "--stream", "2.*.*", "--exclude", "2.0.*", "--exclude", "*.*.*-nightly.*"},
+			expectedOut: "2.1.0 2.1.1",
+		},
+		{
+			name:        "Provided versions highest without the nightlies",
+			inputArgs:   []string{"--versions", "2.1.0 2.2.0-rc.1 2.2.0-nightly.1", "--exclude", "*.*.*-nightly.*", "--highest"},
+			expectedOut: "2.2.0-rc.1",
+		},
+		{
+			name:        "Provided versions deduplicated keeping the first build",
+			inputArgs:   []string{"--versions", "1.2.3+b 1.2.2 1.2.3+a 1.2.3", "--dedupe", "first"},
+			expectedOut: "1.2.3+b 1.2.2",
+		},
+		{
+			name:        "Provided versions deduplicated keeping the last build",
+			inputArgs:   []string{"--versions", "1.2.3+b 1.2.2 1.2.3+a", "--dedupe", "last"},
+			expectedOut: "1.2.2 1.2.3+a",
+		},
+		{
+			name:        "Provided versions highest of equal precedence",
+			inputArgs:   []string{"--versions", "1.2.3+b 1.2.3+a 1.2.2", "--highest"},
+			expectedOut: "1.2.3+a",
+		},
 	}
 
 	for _, test := range tests {
@@ -52,6 +145,102 @@ func TestNewFilterCommand(t *testing.T) {
 	}
 }
 
+func TestNewFilterCommandInvalidVersions(t *testing.T) {
+	t.Run("Provided multiple versions highest with some bad versions", func(t *testing.T) {
+		filterArgs := &FilterArgs{}
+		output, errOutput, err := executeCommandWithStderr(NewFilterCommand(filterArgs), "--versions", "1.2.3, 1.1.1, bad.version", "--highest")
+
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.3", output)
+		assert.Contains(t, errOutput, `warning: invalid version "bad.version"`)
+		assert.Contains(t, errOutput, "warning: ignored 1 invalid versions, use --strict-input to reject them")
+	})
+}
+
+func TestNewFilterCommandVersionsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "versions.txt")
+	require.NoError(t, os.WriteFile(path, []byte("1.0.0\n2.0.0\nbad.version\n1.1.0\n"), 0o600))
+
+	tests := []struct {
+		name            string
+		inputArgs       []string
+		stdin           io.Reader
+		expectedOut     string
+		expectedWarning string
+		expectedError   string
+	}{
+		{
+			name:            "Versions file",
+			inputArgs:       []string{"--versions-file", path, "--stream", "1.*.*"},
+			expectedOut:     "1.0.0 1.1.0",
+			expectedWarning: `warning: line 3: invalid version "bad.version"`,
+		},
+		{
+			name:            "Invalid versions are summarized",
+			inputArgs:       []string{"--versions", "1.2.0 1.2 latest", "--versions-file", path},
+			expectedOut:     "1.2.0 1.0.0 2.0.0 1.1.0",
+			expectedWarning: "warning: ignored 3 invalid versions, use --strict-input to reject them",
+		},
+		{
+			name:          "Strict input",
+			inputArgs:     []string{"--versions-file", path, "--strict-input"},
+			expectedError: `1 invalid versions in the input: line 3: invalid version "bad.version"`,
+		},
+		{
+			name:        "Versions file from stdin",
+			inputArgs:   []string{"--versions-file", "-", "--highest"},
+			stdin:       strings.NewReader("1.0.0,1.1.0\n0.9.0"),
+			expectedOut: "1.1.0",
+		},
+		{
+			name:        "Stdin is not read without --versions-file -",
+			inputArgs:   []string{"--stream", "2.*.*"},
+			stdin:       strings.NewReader("1.0.0\n2.0.0\n2.1.0\n"),
+			expectedOut: "",
+		},
+		{
+			name:        "Versions file merged with versions",
+			inputArgs:   []string{"--versions", "3.0.0", "--versions-file", path, "--highest"},
+			expectedOut: "3.0.0",
+		},
+		{
+			name:        "Piped namespaced tags",
+			inputArgs:   []string{"--namespace", "service-a/v", "--versions-file", "-", "--highest"},
+			stdin:       strings.NewReader("service-a/v1.4.2\nservice-b/v2.0.0\nservice-a/v1.3.0\n"),
+			expectedOut: "1.4.2",
+		},
+		{
+			name:        "Namespaced versions drop other entries",
+			inputArgs:   []string{"--namespace", "service-a/v", "--versions", "service-a/v1.0.0,2.0.0,service-b/v3.0.0", "--highest", "--strict-input"},
+			expectedOut: "1.0.0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filterArgs := &FilterArgs{}
+			filtercmd := NewFilterCommand(filterArgs)
+			output := new(bytes.Buffer)
+			errOutput := new(bytes.Buffer)
+			filtercmd.SetOut(output)
+			filtercmd.SetErr(errOutput)
+			filtercmd.SetIn(test.stdin)
+			filtercmd.SetArgs(test.inputArgs)
+
+			err := filtercmd.Execute()
+			if test.expectedError != "" {
+				assert.ErrorContains(t, err, test.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedOut, strings.TrimSpace(output.String()))
+			if test.expectedWarning != "" {
+				assert.Contains(t, errOutput.String(), test.expectedWarning)
+			}
+		})
+	}
+}
+
 func executeCommand(cmd *cobra.Command, args ...string) (string, error) {
 	buf := new(bytes.Buffer)
 	cmd.SetOut(buf)
@@ -60,3 +249,14 @@ func executeCommand(cmd *cobra.Command, args ...string) (string, error) {
 	err := cmd.Execute()
 	return strings.TrimSpace(buf.String()), err
 }
+
+// executeCommandWithStderr is executeCommand with the warnings of stderr
+// returned apart from the output
+func executeCommandWithStderr(cmd *cobra.Command, args ...string) (string, string, error) {
+	buf, errBuf := new(bytes.Buffer), new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(errBuf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return strings.TrimSpace(buf.String()), errBuf.String(), err
+}
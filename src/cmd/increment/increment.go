@@ -13,8 +13,13 @@ type config struct {
 	dryRun         bool
 	incrementType  string
 	sourceVersions string
+	sourceFile     string
 	repository     string
 	targetStream   string
+	exclude        []string
+	namespace      string
+	prefixStyle    string
+	strictInput    bool
 }
 
 func NewIncrementCommand() *cobra.Command {
@@ -27,11 +32,11 @@ Increment a version according to one of the required flags --level or --target-s
 and any combination of the optional flags:
 
 - Use --level to specify the increment level (major, minor, patch).
-- Define the source with --repository, --source-stream, --source-version, or --source-versions. (Only --source-versions is currently implemented)
+- Define the source with --repository, --source-stream, --source-version, --source-versions or --source-file. (Only --source-versions and --source-file are currently implemented)
 
 Increment a version according to the provided:
   - Increment level (major, minor, patch)
-  - The source, any of: repository, source-stream, source-version, source-versions
+  - The source, any of: repository, source-stream, source-version, source-versions, source-file
   - The target stream, if specified, e.g. 1.2.* (optional)
   `,
 		PersistentPreRun: func(cmd *cobra.Command, args []string) {
@@ -57,15 +62,55 @@ Increment a version according to the provided:
 	incrementCmd.Flags().StringVarP(&config.incrementType, "level", "l", string(models.Patch), "The level of increment to perform, options: major, minor, patch (defaults to patch if --target-stream not specified)")
 	incrementCmd.Flags().StringVarP(&config.targetStream, "target-stream", "t", "", "The target stream to increment to e.g. 1.2.* (optional)")
 	incrementCmd.Flags().StringVarP(&config.sourceVersions, "source-versions", "s", "", "The source versions to increment from e.g. \"0.0.0,1.0.0,1.1.0\" (optional)")
+	incrementCmd.Flags().StringVarP(&config.sourceFile, "source-file", "f", "", "File of newline, comma or space separated source versions, \"-\" reads stdin (optional)")
+	incrementCmd.Flags().StringArrayVar(&config.exclude, "exclude", []string{}, "Ignore the source versions matching a pattern e.g. a retracted 1.4.* line or *.*.*-nightly.* (repeatable)")
+	incrementCmd.Flags().StringVar(&config.namespace, "namespace", "", "The tag template of a monorepo component e.g. service-a/v or lib/core@{version}, only its source versions are read and the new version is printed in it (optional)")
+	incrementCmd.Flags().BoolVar(&config.strictInput, "strict-input", false, "Fail on invalid source versions instead of ignoring them with a warning")
+	incrementCmd.Flags().StringVar(&config.prefixStyle, "prefix-style", string(models.PrefixKeep), "How the v prefix of the new version is printed, options: keep (the style of the highest source version), strip, add")
 	// incrementCmd.Flags().StringVarP(&config.repository, "repository", "r", "", "The repository to increment the version of e.g. https://github.com/<user|org>/<repo> (optional)")
 
 	return incrementCmd
 }
 
 func RunIncrement(config *config, cmd *cobra.Command) error {
-	sourceVersions := filter.GetValidVersions(config.sourceVersions)
+	namespace, err := models.ParseNamespace(config.namespace)
+	if err != nil {
+		return err
+	}
+	prefixStyle, err := models.ParsePrefixStyle(config.prefixStyle)
+	if err != nil {
+		return err
+	}
+
+	source := utils.ParseVersions(config.sourceVersions, namespace)
+	if config.sourceFile != "" {
+		fileSource, err := utils.ReadVersionsFile(cmd, config.sourceFile, namespace)
+		if err != nil {
+			return err
+		}
+		source.Append(fileSource)
+	}
+	if err := utils.CheckInput(cmd, source.Errors, config.strictInput); err != nil {
+		return err
+	}
+
+	sourceVersions := source.Versions
+	if len(config.exclude) > 0 {
+		patterns, err := models.ParseVersionPatterns(config.exclude)
+		if err != nil {
+			return err
+		}
+		sourceVersions, err = filter.ApplyFilters(sourceVersions, filter.ExcludePatternFilter(patterns...))
+		if err != nil {
+			return err
+		}
+	}
+	if len(sourceVersions) == 0 {
+		// without source versions, given or left after dropping the invalid,
+		// excluded and other namespace ones, the increment starts from 0.0.0
+		sourceVersions = []models.Version{{}}
+	}
 
-	var err error
 	var targetStream models.VersionPattern
 	if config.targetStream != "" {
 		targetStream, err = models.ParseVersionPattern(config.targetStream)
@@ -78,6 +123,11 @@ func RunIncrement(config *config, cmd *cobra.Command) error {
 	if err != nil {
 		return err
 	}
-	cmd.Print(newVersion.String())
+	if prefixStyle == models.PrefixAdd && namespace.HasVersionPrefix() {
+		// the template already writes the v prefix of the new version
+		prefixStyle = models.PrefixStrip
+	}
+	newVersion = prefixStyle.Apply(newVersion)
+	cmd.Print(namespace.Apply(newVersion.String()))
 	return nil
 }
@@ -2,10 +2,14 @@ package increment
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/joho/godotenv"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type testFlag struct {
@@ -35,7 +39,7 @@ func TestNewIncrementCommand(t *testing.T) {
 	t.Run("Command has expected flags", func(t *testing.T) {
 		cmd := NewIncrementCommand()
 		flags := cmd.Flags()
-		expectedFlags := []string{"level", "source-versions", "target-stream"}
+		expectedFlags := []string{"level", "source-versions", "source-file", "target-stream", "exclude", "namespace", "prefix-style", "strict-input"}
 		for _, expectedFlag := range expectedFlags {
 			assert.NotNil(t, flags.Lookup(expectedFlag))
 
@@ -109,6 +113,62 @@ func TestNewIncrementCommandFlags(t *testing.T) {
 			expectedNewVersion: "0.2.0-alpha.0",
 			expectedError:      nil,
 		},
+		{
+			name: "Increment v prefixed source versions",
+			flags: []testFlag{
+				{name: "level", value: "minor"},
+				{name: "source-versions", value: "v0.1.0,v1.0.0,0.9.0"},
+			},
+			expectedNewVersion: "v1.1.0",
+			expectedError:      nil,
+		},
+		{
+			name: "Increment v prefixed source versions with stripped prefix",
+			flags: []testFlag{
+				{name: "source-versions", value: "v1.0.0"},
+				{name: "prefix-style", value: "strip"},
+			},
+			expectedNewVersion: "1.0.1",
+			expectedError:      nil,
+		},
+		{
+			name: "Increment with added prefix",
+			flags: []testFlag{
+				{name: "level", value: "major"},
+				{name: "prefix-style", value: "add"},
+			},
+			expectedNewVersion: "v1.0.0",
+			expectedError:      nil,
+		},
+		{
+			name: "Increment without the excluded source versions",
+			flags: []testFlag{
+				{name: "level", value: "minor"},
+				{name: "source-versions", value: "1.0.0,1.1.0,2.0.0,2.0.1,1.2.0-nightly.3"},
+				{name: "exclude", value: "2.0.*"},
+				{name: "exclude", value: "*.*.*-nightly.*"},
+			},
+			expectedNewVersion: "1.2.0",
+			expectedError:      nil,
+		},
+		{
+			name: "Increment from 0.0.0 when every source version is excluded",
+			flags: []testFlag{
+				{name: "source-versions", value: "v1.2.3"},
+				{name: "exclude", value: "1.*.*"},
+			},
+			expectedNewVersion: "0.0.1",
+			expectedError:      nil,
+		},
+		{
+			name: "Increment from 0.0.0 without source versions in the namespace",
+			flags: []testFlag{
+				{name: "namespace", value: "service-b/v"},
+				{name: "source-versions", value: "service-a/v1.0.0"},
+			},
+			expectedNewVersion: "service-b/v0.0.1",
+			expectedError:      nil,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -128,3 +188,125 @@ func TestNewIncrementCommandFlags(t *testing.T) {
 		})
 	}
 }
+
+func TestNewIncrementCommandSourceFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "versions.txt")
+	require.NoError(t, os.WriteFile(path, []byte("0.0.0\n1.0.0, 0.1.0\nbad.version\n"), 0o600))
+
+	tests := []struct {
+		name               string
+		flags              []testFlag
+		stdin              string
+		expectedNewVersion string
+		expectedWarning    string
+	}{
+		{
+			name: "Increment major version with source file",
+			flags: []testFlag{
+				{name: "level", value: "major"},
+				{name: "source-file", value: path},
+			},
+			expectedNewVersion: "2.0.0",
+			expectedWarning:    `warning: line 3: invalid version "bad.version"`,
+		},
+		{
+			name: "Increment minor version with source versions from stdin",
+			flags: []testFlag{
+				{name: "level", value: "minor"},
+				{name: "source-file", value: "-"},
+			},
+			stdin:              "1.0.0\n1.2.0\n",
+			expectedNewVersion: "1.3.0",
+		},
+		{
+			name: "Source file merged with source versions",
+			flags: []testFlag{
+				{name: "level", value: "patch"},
+				{name: "source-versions", value: "1.0.5"},
+				{name: "source-file", value: "-"},
+			},
+			stdin:              "1.0.0 1.0.2",
+			expectedNewVersion: "1.0.6",
+		},
+		{
+			name: "Namespaced source versions",
+			flags: []testFlag{
+				{name: "level", value: "minor"},
+				{name: "namespace", value: "lib/core@{version}"},
+				{name: "source-file", value: "-"},
+			},
+			stdin:              "lib/core@1.2.0\nlib/util@3.0.0\nlib/core@1.1.4\n",
+			expectedNewVersion: "lib/core@1.3.0",
+		},
+		{
+			name: "Added prefix with a v namespace",
+			flags: []testFlag{
+				{name: "level", value: "minor"},
+				{name: "namespace", value: "service-a/v"},
+				{name: "source-versions", value: "service-a/v1.0.0"},
+				{name: "prefix-style", value: "add"},
+			},
+			expectedNewVersion: "service-a/v1.1.0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := new(bytes.Buffer)
+			errOutput := new(bytes.Buffer)
+
+			cmd := NewIncrementCommand()
+			cmd.SetOut(output)
+			cmd.SetErr(errOutput)
+			cmd.SetIn(strings.NewReader(tt.stdin))
+
+			for _, flag := range tt.flags {
+				cmd.Flags().Set(flag.name, flag.value)
+			}
+
+			err := cmd.Execute()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedNewVersion, output.String())
+			if tt.expectedWarning != "" {
+				assert.Contains(t, errOutput.String(), tt.expectedWarning)
+			}
+		})
+	}
+}
+
+func TestNewIncrementCommandStrictInput(t *testing.T) {
+	t.Run("Invalid source versions are ignored with a warning", func(t *testing.T) {
+		output := new(bytes.Buffer)
+		errOutput := new(bytes.Buffer)
+		cmd := NewIncrementCommand()
+		cmd.SetOut(output)
+		cmd.SetErr(errOutput)
+		cmd.SetArgs([]string{"--source-versions", "1.0.0,bad.version"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "1.0.1", output.String())
+		assert.Contains(t, errOutput.String(), `warning: invalid version "bad.version"`)
+		assert.Contains(t, errOutput.String(), "warning: ignored 1 invalid versions")
+	})
+
+	t.Run("Increment from 0.0.0 when every source version is invalid", func(t *testing.T) {
+		output := new(bytes.Buffer)
+		errOutput := new(bytes.Buffer)
+		cmd := NewIncrementCommand()
+		cmd.SetOut(output)
+		cmd.SetErr(errOutput)
+		cmd.SetArgs([]string{"--source-versions", "bad"})
+
+		require.NoError(t, cmd.Execute())
+		assert.Equal(t, "0.0.1", output.String())
+		assert.Contains(t, errOutput.String(), "warning: ignored 1 invalid versions")
+	})
+
+	t.Run("Invalid source versions fail with strict input", func(t *testing.T) {
+		cmd := NewIncrementCommand()
+		cmd.SetOut(new(bytes.Buffer))
+		cmd.SetErr(new(bytes.Buffer))
+		cmd.SetArgs([]string{"--source-versions", "1.0.0,bad.version", "--strict-input"})
+
+		assert.ErrorContains(t, cmd.Execute(), `1 invalid versions in the input: invalid version "bad.version"`)
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 
+	"src/cmd/smgr/cmd/cache"
 	"src/cmd/smgr/cmd/fetch"
 	"src/cmd/smgr/cmd/filter"
 	"src/cmd/smgr/cmd/increment"
@@ -43,7 +44,8 @@ func NewRootCommand(output io.Writer) *cobra.Command {
 	fetchCmd := fetch.NewFetchCommand(filterArgs)
 	fetchCmd.Flags().AddFlagSet(filterCmd.Flags())
 	incrementCmd := increment.NewIncrementCommand()
-	cmd.AddCommand(filterCmd, fetchCmd, incrementCmd)
+	cacheCmd := cache.NewCacheCommand()
+	cmd.AddCommand(filterCmd, fetchCmd, incrementCmd, cacheCmd)
 
 	return cmd
 }
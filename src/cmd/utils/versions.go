@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"src/cmd/smgr/datasource/file"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/pkg/fetch"
+	"src/cmd/smgr/pkg/filter"
+	"src/cmd/smgr/utils"
+
+	"github.com/spf13/cobra"
+)
+
+// ReadVersionsFile reads the versions of a plain text file, or of the command
+// input when path is "-", with the errors of its invalid entries. With a
+// namespace only its entries are read.
+func ReadVersionsFile(cmd *cobra.Command, path string, namespace models.Namespace) (filter.ParseResult, error) {
+	fetcher := file.NewFetcher(&utils.DatasourceConfig{Platform: "file", Repository: path, Namespace: namespace})
+	fetcher.Stdin = cmd.InOrStdin()
+
+	versions, err := fetcher.FetchTags()
+	if err != nil {
+		return filter.ParseResult{}, err
+	}
+	return filter.ParseResult{Versions: versions, Errors: fetcher.EntryErrors()}, nil
+}
+
+// ParseVersions parses a comma or space separated version list. With a
+// namespace only its entries are parsed, stripped of its template.
+func ParseVersions(versions string, namespace models.Namespace) filter.ParseResult {
+	entries := []string{}
+	for _, entry := range models.SplitVersions(versions) {
+		if version, found := namespace.Strip(entry); found {
+			entries = append(entries, version)
+		}
+	}
+	return filter.ParseVersionLists(strings.Join(entries, ","))
+}
+
+// CheckInput prints on stderr the invalid entries of the input followed by
+// a summary, with strict they fail the command instead
+func CheckInput(cmd *cobra.Command, entryErrors []error, strict bool) error {
+	if len(entryErrors) == 0 {
+		return nil
+	}
+	if strict {
+		return fmt.Errorf("%d invalid versions in the input: %w", len(entryErrors), errors.Join(entryErrors...))
+	}
+
+	for _, err := range entryErrors {
+		cmd.PrintErrf("warning: %v\n", err)
+	}
+	cmd.PrintErrf("warning: ignored %d invalid versions, use --strict-input to reject them\n", len(entryErrors))
+	return nil
+}
+
+// EntryErrors returns the invalid entries of the last fetch of an EntryReporter
+func EntryErrors(fetcher fetch.Fetcher) []error {
+	if reporter, ok := fetcher.(fetch.EntryReporter); ok {
+		return reporter.EntryErrors()
+	}
+	return nil
+}
+
+// YankedVersions returns the yanked versions of the last fetch of a
+// YankedLister, the entries that are not valid versions are left out
+func YankedVersions(fetcher fetch.Fetcher) []models.Version {
+	lister, ok := fetcher.(fetch.YankedLister)
+	if !ok {
+		return nil
+	}
+	return filter.GetValidVersions(strings.Join(lister.Yanked(), ","))
+}
+
+// ReportWarnings prints on stderr the warnings of a Warner
+func ReportWarnings(cmd *cobra.Command, fetcher fetch.Fetcher) {
+	if warner, ok := fetcher.(fetch.Warner); ok {
+		for _, warning := range warner.Warnings() {
+			cmd.PrintErrf("warning: %s\n", warning)
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+
+	"k8s.io/klog"
+)
+
+const tagsPerPage = 100
+
+type BitbucketClient struct {
+	config     *utils.DatasourceConfig
+	httpClient *http.Client
+	skipped    []string
+}
+
+// tagPage is a page of the paged tags API of Bitbucket Server
+type tagPage struct {
+	Values []struct {
+		DisplayID string `json:"displayId"`
+	} `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+func NewFetcher(config *utils.DatasourceConfig) *BitbucketClient {
+	return &BitbucketClient{
+		config:     config,
+		httpClient: datasourceUtils.NewHTTPClient(config),
+	}
+}
+
+// FetchTags lists every tag of the configured Bitbucket Server or Data
+// Center repository and returns the Semver compliant ones, non compliant
+// tags are recorded as skipped
+func (b *BitbucketClient) FetchTags() ([]models.Version, error) {
+	if b.config.BaseURL == "" {
+		return nil, errors.New("bitbucket-server: base URL is required, e.g. https://bitbucket.example.com")
+	}
+	project, repo, err := b.repository()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := b.listTags(project, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, skipped := datasourceUtils.ParseTags(datasourceUtils.NamespaceTags(b.config.Namespace, tags))
+	b.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver tags: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the tags of the last fetch that were not Semver compliant
+func (b *BitbucketClient) Skipped() []string {
+	return b.skipped
+}
+
+// repository returns the project key and the slug of the repository. The
+// owner is the project key, ~user for a personal repository, and the
+// repository may also be passed as PROJECT/repo.
+func (b *BitbucketClient) repository() (string, string, error) {
+	project := strings.Trim(b.config.Owner, "/")
+	repo := strings.Trim(b.config.Repository, "/")
+	if project == "" {
+		project, repo, _ = strings.Cut(repo, "/")
+	}
+	if project == "" || repo == "" {
+		return "", "", errors.New("bitbucket-server: project key and repository slug are required")
+	}
+	return project, repo, nil
+}
+
+func (b *BitbucketClient) listTags(project, repo string) ([]string, error) {
+	tagList := []string{}
+	baseURL := fmt.Sprintf("%s/rest/api/1.0/projects/%s/repos/%s/tags",
+		strings.TrimSuffix(b.config.BaseURL, "/"), url.PathEscape(project), url.PathEscape(repo))
+
+	for start := 0; ; {
+		req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s?start=%d&limit=%d", baseURL, start, tagsPerPage), nil)
+		if err != nil {
+			return nil, fmt.Errorf("ListTags error: %w", err)
+		}
+		b.authorize(req)
+
+		page, err := b.getTags(req)
+		if err != nil {
+			return nil, datasourceUtils.WrapError("ListTags error", err)
+		}
+
+		for _, tag := range page.Values {
+			tagList = append(tagList, tag.DisplayID)
+		}
+		if page.IsLastPage || page.NextPageStart <= start {
+			break
+		}
+		start = page.NextPageStart
+	}
+
+	return tagList, nil
+}
+
+func (b *BitbucketClient) getTags(req *http.Request) (*tagPage, error) {
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", req.URL.Redacted(), resp.Status)
+	}
+
+	page := &tagPage{}
+	if err := json.NewDecoder(resp.Body).Decode(page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// authorize sends the token as a bearer HTTP access token, or as the
+// password of the username when one is set
+func (b *BitbucketClient) authorize(req *http.Request) {
+	if b.config.Token == "" {
+		return
+	}
+	if b.config.Username != "" {
+		req.SetBasicAuth(b.config.Username, b.config.Token)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+b.config.Token)
+}
@@ -0,0 +1,135 @@
+package bitbucket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"src/cmd/smgr/utils"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves the tags of PROJ/app in pages starting at the index
+// of their first tag, it requires the "secret" token
+func newTestServer(t *testing.T, pages [][]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/1.0/projects/PROJ/repos/app/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		username, password, basic := r.BasicAuth()
+		if r.Header.Get("Authorization") != "Bearer secret" && !(basic && username == "ci" && password == "secret") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+		offset, page := 0, 0
+		for page < len(pages)-1 && offset+len(pages[page]) <= start {
+			offset += len(pages[page])
+			page++
+		}
+
+		values := []map[string]string{}
+		for _, name := range pages[page] {
+			values = append(values, map[string]string{"id": "refs/tags/" + name, "displayId": name})
+		}
+		body := map[string]interface{}{
+			"values":     values,
+			"start":      start,
+			"isLastPage": page == len(pages)-1,
+		}
+		if page < len(pages)-1 {
+			body["nextPageStart"] = offset + len(pages[page])
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(body)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestBitbucketClient_FetchTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      utils.DatasourceConfig
+		pages       [][]string
+		want        []string
+		wantSkipped []string
+	}{
+		{
+			name:   "Project key as owner with bearer token",
+			config: utils.DatasourceConfig{Owner: "PROJ", Repository: "app", Token: "secret"},
+			pages:  [][]string{{"1.0.0", "1.1.0"}},
+			want:   []string{"1.0.0", "1.1.0"},
+		},
+		{
+			name:        "Repository path, basic auth and pagination",
+			config:      utils.DatasourceConfig{Repository: "PROJ/app", Username: "ci", Token: "secret"},
+			pages:       [][]string{{"1.0.0", "release-1.0.1"}, {"1.1.0"}, {"2.0.0-rc.1"}},
+			want:        []string{"1.0.0", "1.1.0", "2.0.0-rc.1"},
+			wantSkipped: []string{"release-1.0.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.pages)
+			config := tt.config
+			config.BaseURL = server.URL + "/"
+			fetcher := NewFetcher(&config)
+
+			versions, err := fetcher.FetchTags()
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, version := range versions {
+				got = append(got, version.String())
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, fetcher.Skipped())
+		})
+	}
+}
+
+func TestBitbucketClient_FetchTagsErrors(t *testing.T) {
+	server := newTestServer(t, [][]string{{"1.0.0"}})
+
+	tests := []struct {
+		name    string
+		config  utils.DatasourceConfig
+		wantErr string
+	}{
+		{
+			name:    "Missing base URL",
+			config:  utils.DatasourceConfig{Owner: "PROJ", Repository: "app"},
+			wantErr: "base URL is required",
+		},
+		{
+			name:    "Missing project",
+			config:  utils.DatasourceConfig{BaseURL: server.URL, Repository: "app"},
+			wantErr: "project key and repository slug are required",
+		},
+		{
+			name:    "Unknown repository",
+			config:  utils.DatasourceConfig{BaseURL: server.URL, Owner: "PROJ", Repository: "lib", Token: "secret"},
+			wantErr: "404 Not Found",
+		},
+		{
+			name:    "Missing token",
+			config:  utils.DatasourceConfig{BaseURL: server.URL, Owner: "PROJ", Repository: "app"},
+			wantErr: "401 Unauthorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFetcher(&tt.config).FetchTags()
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package crates
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+
+	"k8s.io/klog"
+)
+
+const defaultIndex = "https://index.crates.io"
+
+type CratesClient struct {
+	config     *utils.DatasourceConfig
+	httpClient *http.Client
+	skipped    []string
+	yanked     []string
+}
+
+// indexEntry is a line of the sparse index file of a crate
+type indexEntry struct {
+	Name   string `json:"name"`
+	Vers   string `json:"vers"`
+	Yanked bool   `json:"yanked"`
+}
+
+func NewFetcher(config *utils.DatasourceConfig) *CratesClient {
+	return &CratesClient{
+		config:     config,
+		httpClient: datasourceUtils.NewHTTPClient(config),
+	}
+}
+
+// FetchTags reads the published versions of the configured crate from a
+// sparse registry index. Yanked versions are kept since their numbers
+// cannot be published again.
+func (c *CratesClient) FetchTags() ([]models.Version, error) {
+	name := strings.TrimSpace(c.config.Repository)
+	if name == "" {
+		return nil, errors.New("crates: crate name is required")
+	}
+
+	entries, err := c.getIndexEntries(name)
+	if err != nil {
+		return nil, err
+	}
+
+	rawVersions := []string{}
+	c.yanked = []string{}
+	for _, entry := range entries {
+		rawVersions = append(rawVersions, entry.Vers)
+		if entry.Yanked {
+			c.yanked = append(c.yanked, entry.Vers)
+		}
+	}
+
+	versions, skipped := datasourceUtils.ParseTags(rawVersions)
+	c.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver versions: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the versions of the last fetch that were not Semver compliant
+func (c *CratesClient) Skipped() []string {
+	return c.skipped
+}
+
+// Yanked returns the versions of the last fetch that were yanked
+func (c *CratesClient) Yanked() []string {
+	return c.yanked
+}
+
+// Warnings lists the versions of the last fetch that did not map to Semver
+func (c *CratesClient) Warnings() []string {
+	return datasourceUtils.UnmappedWarnings("crates", c.skipped)
+}
+
+// IndexPath returns the path of the index file of a crate, e.g.
+// 1/a, 2/ab, 3/a/abc and se/rd/serde
+func IndexPath(name string) string {
+	name = strings.ToLower(name)
+	switch len(name) {
+	case 1:
+		return "1/" + name
+	case 2:
+		return "2/" + name
+	case 3:
+		return "3/" + name[:1] + "/" + name
+	default:
+		return name[:2] + "/" + name[2:4] + "/" + name
+	}
+}
+
+func (c *CratesClient) baseURL() string {
+	if c.config.BaseURL == "" {
+		return defaultIndex
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(c.config.BaseURL, "sparse+"), "/")
+}
+
+func (c *CratesClient) getIndexEntries(name string) ([]indexEntry, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL()+"/"+IndexPath(name), nil)
+	if err != nil {
+		return nil, fmt.Errorf("crates: index error: %w", err)
+	}
+	if c.config.Token != "" {
+		req.Header.Set("Authorization", c.config.Token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, datasourceUtils.WrapError("crates: index error", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("crates: index error: GET %s: %s", req.URL.Redacted(), resp.Status)
+	}
+
+	entries := []indexEntry{}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry indexEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("crates: index error: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("crates: index error: %w", err)
+	}
+	return entries, nil
+}
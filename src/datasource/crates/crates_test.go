@@ -0,0 +1,67 @@
+package crates
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"src/cmd/smgr/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexPath(t *testing.T) {
+	assert.Equal(t, "1/a", IndexPath("a"))
+	assert.Equal(t, "2/ab", IndexPath("ab"))
+	assert.Equal(t, "3/a/abc", IndexPath("abc"))
+	assert.Equal(t, "se/rd/serde", IndexPath("Serde"))
+}
+
+func TestCratesClient_FetchTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/se/rd/serde":
+			fmt.Fprintln(w, `{"name":"serde","vers":"1.0.0","yanked":false}`)
+			fmt.Fprintln(w, `{"name":"serde","vers":"1.0.1","yanked":true}`)
+			fmt.Fprintln(w, `{"name":"serde","vers":"1.1.0-rc.1","yanked":false}`)
+			fmt.Fprintln(w, `{"name":"serde","vers":"1.0","yanked":false}`)
+		case "/3/f/foo":
+			if r.Header.Get("Authorization") != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintln(w, `{"name":"foo","vers":"0.1.0","yanked":false}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("Public crate", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: "sparse+" + server.URL + "/", Repository: "serde"})
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+
+		got := []string{}
+		for _, version := range versions {
+			got = append(got, version.String())
+		}
+		assert.Equal(t, []string{"1.0.0", "1.0.1", "1.1.0-rc.1"}, got)
+		assert.Equal(t, []string{"1.0.1"}, fetcher.Yanked())
+		assert.Equal(t, []string{"crates: 1 versions do not map to Semver: 1.0"}, fetcher.Warnings())
+	})
+
+	t.Run("Private registry with token", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "foo", Token: "secret"})
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+		assert.Len(t, versions, 1)
+	})
+
+	t.Run("Unknown crate", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "unknown"})
+		_, err := fetcher.FetchTags()
+		assert.ErrorContains(t, err, "404 Not Found")
+	})
+}
@@ -0,0 +1,146 @@
+package file
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+
+	"k8s.io/klog"
+)
+
+// Stdin is the path that reads the versions from the standard input
+const Stdin = "-"
+
+// EntryError describes an entry of the input that is not a valid version
+type EntryError struct {
+	Line  int
+	Entry string
+	Err   error
+}
+
+func (e *EntryError) Error() string {
+	return fmt.Sprintf("line %d: invalid version %q: %v", e.Line, e.Entry, e.Err)
+}
+
+func (e *EntryError) Unwrap() error {
+	return e.Err
+}
+
+type FileClient struct {
+	config *utils.DatasourceConfig
+	// Stdin is read instead of a file when the repository is "-"
+	Stdin   io.Reader
+	skipped []string
+	errors  []error
+}
+
+func NewFetcher(config *utils.DatasourceConfig) *FileClient {
+	return &FileClient{
+		config: config,
+		Stdin:  os.Stdin,
+	}
+}
+
+// FetchTags reads the versions of a plain text file, or of the standard
+// input when the repository is "-". Versions may be separated by newlines,
+// commas or spaces and the text after a # is ignored, entries that are not
+// valid versions are recorded with their line number. With a namespace only
+// its entries are read.
+func (f *FileClient) FetchTags() ([]models.Version, error) {
+	reader, closeReader, err := f.open()
+	if err != nil {
+		return nil, err
+	}
+	defer closeReader()
+
+	versions, entryErrors, err := ParseNamespacedVersionList(reader, f.config.Namespace)
+	if err != nil {
+		return nil, fmt.Errorf("file: read error: %w", err)
+	}
+
+	f.skipped = []string{}
+	f.errors = []error{}
+	for _, entryError := range entryErrors {
+		f.skipped = append(f.skipped, entryError.Entry)
+		f.errors = append(f.errors, entryError)
+		klog.V(1).Info(entryError.Error())
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the entries of the last fetch that were not valid versions
+func (f *FileClient) Skipped() []string {
+	return f.skipped
+}
+
+// EntryErrors returns an *EntryError for every invalid entry of the last fetch
+func (f *FileClient) EntryErrors() []error {
+	return f.errors
+}
+
+func (f *FileClient) open() (io.Reader, func(), error) {
+	path := f.config.Repository
+	if path == "" {
+		return nil, nil, fmt.Errorf("file: a file path or %q for stdin is required", Stdin)
+	}
+	if path == Stdin {
+		return f.Stdin, func() {}, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("file: %w", err)
+	}
+	return file, func() { file.Close() }, nil
+}
+
+// maxLineSize bounds a line of versions, a whole list may be on one line
+const maxLineSize = 64 * 1024 * 1024
+
+// ParseVersionList parses newline, comma or space separated versions,
+// ignoring the text after a # such as the sources printed by fetch
+func ParseVersionList(r io.Reader) ([]models.Version, []*EntryError, error) {
+	return ParseNamespacedVersionList(r, models.Namespace{})
+}
+
+// ParseNamespacedVersionList is like ParseVersionList but only parses the
+// entries of a namespace, stripped of its template
+func ParseNamespacedVersionList(r io.Reader, namespace models.Namespace) ([]models.Version, []*EntryError, error) {
+	versions := []models.Version{}
+	entryErrors := []*EntryError{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), "#")
+		entries := strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+
+		for _, entry := range entries {
+			rawVersion, found := namespace.Strip(entry)
+			if !found {
+				klog.V(1).Infof("line %d: %q is not in namespace %s", line, entry, namespace)
+				continue
+			}
+			version, err := models.ParseVersion(rawVersion)
+			if err != nil {
+				entryErrors = append(entryErrors, &EntryError{Line: line, Entry: entry, Err: err})
+				continue
+			}
+			versions = append(versions, version)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return versions, entryErrors, nil
+}
@@ -0,0 +1,127 @@
+package file
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersionList(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		namespace  models.Namespace
+		want       []string
+		wantErrors []string
+	}{
+		{
+			name:  "Newline separated",
+			input: "1.0.0\n1.1.0\n\n2.0.0-rc.1\n",
+			want:  []string{"1.0.0", "1.1.0", "2.0.0-rc.1"},
+		},
+		{
+			name:  "Comma and space separated",
+			input: "1.0.0,1.1.0, 1.2.0 2.0.0",
+			want:  []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0"},
+		},
+		{
+			name:       "Invalid entries are reported with their line",
+			input:      "1.0.0\nv1.1.0, 1.2\r\n\nlatest 2.0.0\n",
+			want:       []string{"1.0.0", "v1.1.0", "2.0.0"},
+			wantErrors: []string{`line 2: invalid version "1.2"`, `line 4: invalid version "latest"`},
+		},
+		{
+			name:  "Comments are ignored",
+			input: "# released\n1.0.0 # git:., oci:app\n1.1.0#git:.\n",
+			want:  []string{"1.0.0", "1.1.0"},
+		},
+		{
+			name:       "Namespaced entries",
+			input:      "service-a/v1.0.0\nservice-b/v2.0.0 1.5.0\nservice-a/vlatest\n",
+			namespace:  models.Namespace{Prefix: "service-a/v"},
+			want:       []string{"1.0.0"},
+			wantErrors: []string{`line 3: invalid version "service-a/vlatest"`},
+		},
+		{
+			name:  "Empty input",
+			input: "",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions, entryErrors, err := ParseNamespacedVersionList(strings.NewReader(tt.input), tt.namespace)
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, version := range versions {
+				got = append(got, version.String())
+			}
+			assert.Equal(t, tt.want, got)
+
+			require.Len(t, entryErrors, len(tt.wantErrors))
+			for i, wantError := range tt.wantErrors {
+				assert.ErrorContains(t, entryErrors[i], wantError)
+			}
+		})
+	}
+}
+
+func TestParseVersionListLongLine(t *testing.T) {
+	entries := []string{}
+	for i := 0; i < 20000; i++ {
+		entries = append(entries, fmt.Sprintf("1.%d.0", i))
+	}
+	line := strings.Join(entries, " ")
+	require.Greater(t, len(line), 64*1024)
+
+	versions, entryErrors, err := ParseVersionList(strings.NewReader(line + "\n"))
+	require.NoError(t, err)
+	assert.Empty(t, entryErrors)
+	assert.Len(t, versions, len(entries))
+}
+
+func TestFileClient_FetchTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "versions.txt")
+	require.NoError(t, os.WriteFile(path, []byte("1.0.0\nbad.version\n1.1.0\n"), 0o600))
+
+	t.Run("File", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{Repository: path})
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+		assert.Len(t, versions, 2)
+		assert.Equal(t, []string{"bad.version"}, fetcher.Skipped())
+		require.Len(t, fetcher.EntryErrors(), 1)
+
+		entryError, ok := fetcher.EntryErrors()[0].(*EntryError)
+		require.True(t, ok)
+		assert.Equal(t, 2, entryError.Line)
+	})
+
+	t.Run("Stdin", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{Repository: Stdin})
+		fetcher.Stdin = strings.NewReader("1.0.0 2.0.0")
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+		assert.Len(t, versions, 2)
+		assert.Empty(t, fetcher.EntryErrors())
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := NewFetcher(&utils.DatasourceConfig{Repository: filepath.Join(t.TempDir(), "missing.txt")}).FetchTags()
+		assert.Error(t, err)
+	})
+
+	t.Run("Missing path", func(t *testing.T) {
+		_, err := NewFetcher(&utils.DatasourceConfig{}).FetchTags()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,98 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+
+	"k8s.io/klog"
+)
+
+const defaultRepository = "."
+
+type GitClient struct {
+	config  *utils.DatasourceConfig
+	skipped []string
+}
+
+func NewFetcher(config *utils.DatasourceConfig) *GitClient {
+	return &GitClient{config: config}
+}
+
+// FetchTags lists the lightweight and annotated tags of a repository on disk
+// and returns the Semver compliant ones. Only tags starting with the configured
+// prefix are listed and the prefix is stripped before parsing. When a ref is
+// configured only the tags reachable from it are listed, with a namespace
+// only its tags are kept.
+func (g *GitClient) FetchTags() ([]models.Version, error) {
+	tags, err := g.listTags()
+	if err != nil {
+		return nil, err
+	}
+
+	versions, skipped := datasourceUtils.ParseNormalizedTags(datasourceUtils.NamespaceTags(g.config.Namespace, tags), g.stripPrefix)
+	g.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver tags: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the tags of the last fetch that were not Semver compliant
+func (g *GitClient) Skipped() []string {
+	return g.skipped
+}
+
+func (g *GitClient) repository() string {
+	if g.config.Repository == "" {
+		return defaultRepository
+	}
+	return g.config.Repository
+}
+
+func (g *GitClient) stripPrefix(tag string) string {
+	return strings.TrimPrefix(tag, g.config.TagPrefix)
+}
+
+func (g *GitClient) listTags() ([]string, error) {
+	// a leading dash would be read by git as one of its options
+	if strings.HasPrefix(g.config.Ref, "-") {
+		return nil, fmt.Errorf("git: invalid ref %q", g.config.Ref)
+	}
+	if strings.HasPrefix(g.config.TagPrefix, "-") {
+		return nil, fmt.Errorf("git: invalid tag prefix %q", g.config.TagPrefix)
+	}
+
+	args := []string{"-C", g.repository(), "tag", "--list"}
+	if g.config.Ref != "" {
+		args = append(args, "--merged="+g.config.Ref)
+	}
+	args = append(args, "--", g.config.TagPrefix+"*")
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("git: list tags error: %s", strings.TrimSpace(stderr.String()))
+		}
+		return nil, fmt.Errorf("git: list tags error: %w", err)
+	}
+
+	tags := []string{}
+	for _, tag := range strings.Split(stdout.String(), "\n") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
@@ -0,0 +1,127 @@
+package git
+
+import (
+	"os/exec"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRepository creates a repository whose main branch is tagged
+// 1.0.0 (lightweight), 1.1.0 (annotated), v1.2.0, app/3.0.0 and latest,
+// and whose feature branch is tagged 2.0.0-rc.1
+func newTestRepository(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir, "-c", "user.name=smgr", "-c", "user.email=smgr@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+
+	run("init", "--initial-branch", "main")
+	run("commit", "--allow-empty", "-m", "first")
+	run("tag", "1.0.0")
+	run("commit", "--allow-empty", "-m", "second")
+	run("tag", "-a", "1.1.0", "-m", "release 1.1.0")
+	run("tag", "v1.2.0")
+	run("tag", "app/3.0.0")
+	run("tag", "latest")
+	run("checkout", "-b", "feature")
+	run("commit", "--allow-empty", "-m", "feature")
+	run("tag", "2.0.0-rc.1")
+	run("checkout", "main")
+
+	return dir
+}
+
+func TestGitClient_FetchTags(t *testing.T) {
+	repository := newTestRepository(t)
+
+	tests := []struct {
+		name        string
+		config      utils.DatasourceConfig
+		want        []string
+		wantSkipped []string
+	}{
+		{
+			name:        "All tags",
+			config:      utils.DatasourceConfig{Repository: repository},
+			want:        []string{"1.0.0", "1.1.0", "2.0.0-rc.1", "v1.2.0"},
+			wantSkipped: []string{"app/3.0.0", "latest"},
+		},
+		{
+			name:        "Tags reachable from a ref",
+			config:      utils.DatasourceConfig{Repository: repository, Ref: "main"},
+			want:        []string{"1.0.0", "1.1.0", "v1.2.0"},
+			wantSkipped: []string{"app/3.0.0", "latest"},
+		},
+		{
+			name:   "Tags reachable from an older ref",
+			config: utils.DatasourceConfig{Repository: repository, Ref: "1.0.0"},
+			want:   []string{"1.0.0"},
+		},
+		{
+			name:   "Tag prefix v",
+			config: utils.DatasourceConfig{Repository: repository, TagPrefix: "v"},
+			want:   []string{"1.2.0"},
+		},
+		{
+			name:   "Tag prefix namespace",
+			config: utils.DatasourceConfig{Repository: repository, TagPrefix: "app/"},
+			want:   []string{"3.0.0"},
+		},
+		{
+			name:   "Namespace template",
+			config: utils.DatasourceConfig{Repository: repository, Namespace: models.Namespace{Prefix: "app/"}},
+			want:   []string{"3.0.0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := NewFetcher(&tt.config)
+			versions, err := fetcher.FetchTags()
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, version := range versions {
+				got = append(got, version.String())
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, fetcher.Skipped())
+		})
+	}
+}
+
+func TestGitClient_FetchTagsErrors(t *testing.T) {
+	repository := newTestRepository(t)
+
+	t.Run("Not a repository", func(t *testing.T) {
+		_, err := NewFetcher(&utils.DatasourceConfig{Repository: t.TempDir()}).FetchTags()
+		assert.ErrorContains(t, err, "not a git repository")
+	})
+
+	t.Run("Unknown ref", func(t *testing.T) {
+		_, err := NewFetcher(&utils.DatasourceConfig{Repository: repository, Ref: "unknown"}).FetchTags()
+		assert.ErrorContains(t, err, "git: list tags error")
+	})
+
+	t.Run("Ref read as an option", func(t *testing.T) {
+		_, err := NewFetcher(&utils.DatasourceConfig{Repository: repository, Ref: "--points-at=HEAD"}).FetchTags()
+		assert.ErrorContains(t, err, `git: invalid ref "--points-at=HEAD"`)
+	})
+
+	t.Run("Tag prefix read as an option", func(t *testing.T) {
+		_, err := NewFetcher(&utils.DatasourceConfig{Repository: repository, TagPrefix: "-c"}).FetchTags()
+		assert.ErrorContains(t, err, `git: invalid tag prefix "-c"`)
+	})
+}
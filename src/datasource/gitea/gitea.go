@@ -0,0 +1,139 @@
+package gitea
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+
+	"k8s.io/klog"
+)
+
+const (
+	defaultGiteaURL   = "https://gitea.com"
+	defaultForgejoURL = "https://codeberg.org"
+	tagsPerPage       = 50
+)
+
+type GiteaClient struct {
+	config     *utils.DatasourceConfig
+	httpClient *http.Client
+	skipped    []string
+}
+
+type tag struct {
+	Name string `json:"name"`
+}
+
+func NewFetcher(config *utils.DatasourceConfig) *GiteaClient {
+	return &GiteaClient{
+		config:     config,
+		httpClient: datasourceUtils.NewHTTPClient(config),
+	}
+}
+
+// FetchTags lists every tag of the configured Gitea or Forgejo repository
+// and returns the Semver compliant ones, non compliant tags are recorded
+// as skipped
+func (g *GiteaClient) FetchTags() ([]models.Version, error) {
+	owner, repo, err := g.repository()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := g.listTags(owner, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, skipped := datasourceUtils.ParseTags(datasourceUtils.NamespaceTags(g.config.Namespace, tags))
+	g.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver tags: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the tags of the last fetch that were not Semver compliant
+func (g *GiteaClient) Skipped() []string {
+	return g.skipped
+}
+
+// repository returns the owner and the name of the repository, the
+// repository may also be passed as owner/repo
+func (g *GiteaClient) repository() (string, string, error) {
+	owner := strings.Trim(g.config.Owner, "/")
+	repo := strings.Trim(g.config.Repository, "/")
+	if owner == "" {
+		owner, repo, _ = strings.Cut(repo, "/")
+	}
+	if owner == "" || repo == "" {
+		return "", "", errors.New("gitea: owner and repository are required")
+	}
+	return owner, repo, nil
+}
+
+// baseURL returns the configured instance, or the public instance of the
+// forge: gitea.com for gitea and codeberg.org for forgejo
+func (g *GiteaClient) baseURL() string {
+	if g.config.BaseURL != "" {
+		return strings.TrimSuffix(g.config.BaseURL, "/")
+	}
+	if g.config.Platform == "forgejo" {
+		return defaultForgejoURL
+	}
+	return defaultGiteaURL
+}
+
+func (g *GiteaClient) listTags(owner, repo string) ([]string, error) {
+	tagList := []string{}
+	nextURL := fmt.Sprintf("%s/api/v1/repos/%s/%s/tags?page=1&limit=%d",
+		g.baseURL(), url.PathEscape(owner), url.PathEscape(repo), tagsPerPage)
+
+	for nextURL != "" {
+		req, err := http.NewRequest(http.MethodGet, nextURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("ListTags error: %w", err)
+		}
+		if g.config.Token != "" {
+			req.Header.Set("Authorization", "token "+g.config.Token)
+		}
+
+		tags, resp, err := g.getTags(req)
+		if err != nil {
+			return nil, datasourceUtils.WrapError("ListTags error", err)
+		}
+
+		for _, tag := range tags {
+			tagList = append(tagList, tag.Name)
+		}
+		nextURL = datasourceUtils.NextLink(resp)
+	}
+
+	return tagList, nil
+}
+
+func (g *GiteaClient) getTags(req *http.Request) ([]tag, *http.Response, error) {
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp, fmt.Errorf("GET %s: %s", req.URL.Redacted(), resp.Status)
+	}
+
+	var tags []tag
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, resp, err
+	}
+	return tags, resp, nil
+}
@@ -0,0 +1,130 @@
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"src/cmd/smgr/utils"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves the tags of org/app in numbered pages, it requires
+// the "secret" token
+func newTestServer(t *testing.T, pages [][]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/repos/org/app/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("Authorization") != "token secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 1 || page > len(pages) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if page < len(pages) {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?page=%d&limit=50>; rel="next"`, r.Host, r.URL.Path, page+1))
+		}
+
+		names := []string{}
+		for _, name := range pages[page-1] {
+			names = append(names, fmt.Sprintf(`{"name":%q,"id":"abc"}`, name))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, "[%s]", strings.Join(names, ","))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGiteaClient_FetchTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		config      utils.DatasourceConfig
+		pages       [][]string
+		want        []string
+		wantSkipped []string
+	}{
+		{
+			name:   "Owner and repository",
+			config: utils.DatasourceConfig{Owner: "org", Repository: "app", Token: "secret"},
+			pages:  [][]string{{"1.0.0", "1.1.0"}},
+			want:   []string{"1.0.0", "1.1.0"},
+		},
+		{
+			name:        "Repository path and pagination",
+			config:      utils.DatasourceConfig{Repository: "org/app", Token: "secret", Platform: "forgejo"},
+			pages:       [][]string{{"1.0.0", "release-1.0.1"}, {"1.1.0"}, {"2.0.0-rc.1"}},
+			want:        []string{"1.0.0", "1.1.0", "2.0.0-rc.1"},
+			wantSkipped: []string{"release-1.0.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.pages)
+			config := tt.config
+			config.BaseURL = server.URL + "/"
+			fetcher := NewFetcher(&config)
+
+			versions, err := fetcher.FetchTags()
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, version := range versions {
+				got = append(got, version.String())
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, fetcher.Skipped())
+		})
+	}
+}
+
+func TestGiteaClient_FetchTagsErrors(t *testing.T) {
+	server := newTestServer(t, [][]string{{"1.0.0"}})
+
+	tests := []struct {
+		name    string
+		config  utils.DatasourceConfig
+		wantErr string
+	}{
+		{
+			name:    "Missing owner",
+			config:  utils.DatasourceConfig{BaseURL: server.URL, Repository: "app"},
+			wantErr: "owner and repository are required",
+		},
+		{
+			name:    "Unknown repository",
+			config:  utils.DatasourceConfig{BaseURL: server.URL, Owner: "org", Repository: "lib", Token: "secret"},
+			wantErr: "404 Not Found",
+		},
+		{
+			name:    "Missing token",
+			config:  utils.DatasourceConfig{BaseURL: server.URL, Owner: "org", Repository: "app"},
+			wantErr: "401 Unauthorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFetcher(&tt.config).FetchTags()
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestGiteaClient_baseURL(t *testing.T) {
+	assert.Equal(t, "https://gitea.com", NewFetcher(&utils.DatasourceConfig{Platform: "gitea"}).baseURL())
+	assert.Equal(t, "https://codeberg.org", NewFetcher(&utils.DatasourceConfig{Platform: "forgejo"}).baseURL())
+	assert.Equal(t, "https://git.example.com", NewFetcher(&utils.DatasourceConfig{Platform: "forgejo", BaseURL: "https://git.example.com/"}).baseURL())
+}
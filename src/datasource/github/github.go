@@ -1,18 +1,219 @@
 package github
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
 	"src/cmd/smgr/models"
 	"src/cmd/smgr/utils"
+
+	"github.com/google/go-github/v51/github"
+	"golang.org/x/oauth2"
+	"k8s.io/klog"
+)
+
+const (
+	tagsPerPage   = 100
+	defaultAPIURL = "https://api.github.com"
+
+	// apiURLEnv is set by GitHub Actions to the API URL of the instance
+	apiURLEnv = "GITHUB_API_URL"
 )
 
 type GithubClient struct {
-	config *utils.DatasourceConfig
+	config    *utils.DatasourceConfig
+	client    *github.Client
+	clientErr error
+	skipped   []string
+	releases  []datasourceUtils.Release
 }
 
 func NewFetcher(config *utils.DatasourceConfig) *GithubClient {
-	return &GithubClient{config: config}
+	client, err := newClient(config)
+	return &GithubClient{
+		config:    config,
+		client:    client,
+		clientErr: err,
+	}
+}
+
+// newClient returns a client of github.com, or of the GitHub Enterprise
+// Server instance at the configured API URL
+func newClient(config *utils.DatasourceConfig) (*github.Client, error) {
+	httpClient := datasourceUtils.NewHTTPClient(config)
+	if config.Token != "" {
+		tokenSource := oauth2.StaticTokenSource(
+			&oauth2.Token{AccessToken: config.Token},
+		)
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, httpClient)
+		httpClient = oauth2.NewClient(ctx, tokenSource)
+	}
+
+	apiURL := APIURL(config)
+	if apiURL == defaultAPIURL {
+		return github.NewClient(httpClient), nil
+	}
+	return github.NewEnterpriseClient(apiURL, UploadURL(config), httpClient)
+}
+
+// APIURL returns the configured base URL, the GITHUB_API_URL environment
+// variable or the github.com API URL
+func APIURL(config *utils.DatasourceConfig) string {
+	apiURL := config.BaseURL
+	if apiURL == "" {
+		apiURL = os.Getenv(apiURLEnv)
+	}
+	if apiURL == "" {
+		return defaultAPIURL
+	}
+	return strings.TrimSuffix(apiURL, "/")
+}
+
+// UploadURL returns the configured upload URL, or the root of the API URL
+// which the GitHub client completes with /api/uploads/
+func UploadURL(config *utils.DatasourceConfig) string {
+	if config.UploadURL != "" {
+		return config.UploadURL
+	}
+	return strings.TrimSuffix(APIURL(config), "/api/v3")
 }
 
+// FetchTags lists every tag of the configured repository, or the tag of
+// every release with the releases kind, and returns the Semver compliant
+// ones, non compliant tags are recorded as skipped
 func (g *GithubClient) FetchTags() ([]models.Version, error) {
-	return []models.Version{}, nil
+	if g.clientErr != nil {
+		return nil, fmt.Errorf("github: invalid API URL: %w", g.clientErr)
+	}
+	if g.config.Owner == "" || g.config.Repository == "" {
+		return nil, errors.New("github: owner and repository are required")
+	}
+	if g.config.Kind == datasourceUtils.ReleasesKind {
+		return g.fetchReleases()
+	}
+
+	tags, err := g.listTags()
+	if err != nil {
+		return nil, err
+	}
+
+	versions, skipped := datasourceUtils.ParseTags(datasourceUtils.NamespaceTags(g.config.Namespace, tags))
+	g.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver tags: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the tags of the last fetch that were not Semver compliant
+func (g *GithubClient) Skipped() []string {
+	return g.skipped
+}
+
+// Releases returns the releases of the last fetch with the releases kind
+func (g *GithubClient) Releases() []datasourceUtils.Release {
+	return g.releases
+}
+
+func (g *GithubClient) fetchReleases() ([]models.Version, error) {
+	releases, err := g.listReleases()
+	if err != nil {
+		return nil, err
+	}
+	latestID, err := g.latestReleaseID()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []models.Version{}
+	g.releases = []datasourceUtils.Release{}
+	g.skipped = []string{}
+	for _, release := range releases {
+		tagName, found := g.config.Namespace.Strip(release.GetTagName())
+		if !found {
+			continue
+		}
+		version, err := models.ParseVersion(tagName)
+		if err != nil {
+			g.skipped = append(g.skipped, release.GetTagName())
+			continue
+		}
+		versions = append(versions, version)
+		g.releases = append(g.releases, datasourceUtils.Release{
+			Version:     version,
+			Draft:       release.GetDraft(),
+			Prerelease:  release.GetPrerelease(),
+			Latest:      release.GetID() == latestID,
+			PublishedAt: release.GetPublishedAt().Time,
+		})
+	}
+	if len(g.skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver releases: %s", len(g.skipped), strings.Join(g.skipped, " "))
+	}
+
+	return versions, nil
+}
+
+func (g *GithubClient) listReleases() ([]*github.RepositoryRelease, error) {
+	releaseList := []*github.RepositoryRelease{}
+	ctx := context.Background()
+	opts := &github.ListOptions{PerPage: tagsPerPage, Page: 1}
+
+	for {
+		releases, resp, err := g.client.Repositories.ListReleases(ctx, g.config.Owner, g.config.Repository, opts)
+		if err != nil {
+			return nil, datasourceUtils.WrapError("ListReleases error", err)
+		}
+		releaseList = append(releaseList, releases...)
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return releaseList, nil
+}
+
+// latestReleaseID returns the ID of the release marked latest, or 0 when
+// the repository has none
+func (g *GithubClient) latestReleaseID() (int64, error) {
+	release, resp, err := g.client.Repositories.GetLatestRelease(context.Background(), g.config.Owner, g.config.Repository)
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, datasourceUtils.WrapError("GetLatestRelease error", err)
+	}
+	return release.GetID(), nil
+}
+
+func (g *GithubClient) listTags() ([]string, error) {
+	tagList := []string{}
+	ctx := context.Background()
+	opts := &github.ListOptions{PerPage: tagsPerPage, Page: 1}
+
+	for {
+		tags, resp, err := g.client.Repositories.ListTags(ctx, g.config.Owner, g.config.Repository, opts)
+		if err != nil {
+			return nil, datasourceUtils.WrapError("ListTags error", err)
+		}
+
+		for _, tag := range tags {
+			tagList = append(tagList, tag.GetName())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return tagList, nil
 }
@@ -1 +1,245 @@
 package github
+
+import (
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	datasourceUtils "src/cmd/smgr/datasource/utils"
+	"src/cmd/smgr/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T, pages [][]string) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/tags" {
+			http.NotFound(w, r)
+			return
+		}
+
+		page := 1
+		fmt.Sscanf(r.URL.Query().Get("page"), "%d", &page)
+		if page < len(pages) {
+			w.Header().Set("Link", fmt.Sprintf(`<%s/repos/owner/repo/tags?page=%d>; rel="next"`, server.URL, page+1))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, "[")
+		for i, tag := range pages[page-1] {
+			if i > 0 {
+				fmt.Fprint(w, ",")
+			}
+			fmt.Fprintf(w, `{"name":%q}`, tag)
+		}
+		fmt.Fprint(w, "]")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestFetcher(t *testing.T, server *httptest.Server, config *utils.DatasourceConfig) *GithubClient {
+	t.Helper()
+	fetcher := NewFetcher(config)
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	fetcher.client.BaseURL = baseURL
+	return fetcher
+}
+
+func TestGithubClient_FetchTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		pages       [][]string
+		want        []string
+		wantSkipped []string
+	}{
+		{
+			name:  "Single page",
+			pages: [][]string{{"1.0.0", "1.1.0", "2.0.0-rc.1"}},
+			want:  []string{"1.0.0", "1.1.0", "2.0.0-rc.1"},
+		},
+		{
+			name:  "Multiple pages",
+			pages: [][]string{{"1.0.0", "1.1.0"}, {"1.2.0"}, {"2.0.0+build.1"}},
+			want:  []string{"1.0.0", "1.1.0", "1.2.0", "2.0.0+build.1"},
+		},
+		{
+			name:        "Non-semver tags are skipped",
+			pages:       [][]string{{"1.0.0", "release-1", "1.2"}, {"latest", "1.1.0"}},
+			want:        []string{"1.0.0", "1.1.0"},
+			wantSkipped: []string{"release-1", "1.2", "latest"},
+		},
+		{
+			name:  "No tags",
+			pages: [][]string{{}},
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.pages)
+			fetcher := newTestFetcher(t, server, &utils.DatasourceConfig{Owner: "owner", Repository: "repo"})
+
+			versions, err := fetcher.FetchTags()
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, version := range versions {
+				got = append(got, version.String())
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, fetcher.Skipped())
+		})
+	}
+}
+
+func TestGithubClient_FetchTagsErrors(t *testing.T) {
+	t.Run("Missing owner or repository", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{Repository: "repo"})
+		_, err := fetcher.FetchTags()
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown repository", func(t *testing.T) {
+		server := newTestServer(t, [][]string{{"1.0.0"}})
+		fetcher := newTestFetcher(t, server, &utils.DatasourceConfig{Owner: "owner", Repository: "unknown"})
+		_, err := fetcher.FetchTags()
+		assert.ErrorContains(t, err, "ListTags error")
+	})
+
+	t.Run("Token is sent as bearer", func(t *testing.T) {
+		var authorization string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			authorization = r.Header.Get("Authorization")
+			fmt.Fprint(w, "[]")
+		}))
+		defer server.Close()
+		fetcher := newTestFetcher(t, server, &utils.DatasourceConfig{Owner: "owner", Repository: "repo", Token: "secret"})
+		_, err := fetcher.FetchTags()
+		require.NoError(t, err)
+		assert.Equal(t, "Bearer secret", authorization)
+	})
+}
+
+// writeCABundle writes the certificate of a TLS test server as a PEM bundle
+func writeCABundle(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	require.NoError(t, os.WriteFile(path, bundle, 0o600))
+	return path
+}
+
+func TestGithubClient_Enterprise(t *testing.T) {
+	var authorization string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/owner/repo/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `[{"name":"1.0.0"},{"name":"1.1.0"}]`)
+	}))
+	defer server.Close()
+
+	t.Run("API URL and CA bundle", func(t *testing.T) {
+		transport, err := datasourceUtils.NewCATransport(writeCABundle(t, server))
+		require.NoError(t, err)
+		fetcher := NewFetcher(&utils.DatasourceConfig{Owner: "owner", Repository: "repo", Token: "secret", BaseURL: server.URL, Transport: transport})
+
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+		assert.Len(t, versions, 2)
+		assert.Equal(t, "Bearer secret", authorization)
+		assert.Equal(t, server.URL+"/api/v3/", fetcher.client.BaseURL.String())
+		assert.Equal(t, server.URL+"/api/uploads/", fetcher.client.UploadURL.String())
+	})
+
+	t.Run("API URL from the environment", func(t *testing.T) {
+		t.Setenv("GITHUB_API_URL", server.URL+"/api/v3")
+		transport, err := datasourceUtils.NewCATransport(writeCABundle(t, server))
+		require.NoError(t, err)
+		fetcher := NewFetcher(&utils.DatasourceConfig{Owner: "owner", Repository: "repo", UploadURL: "https://uploads.github.example.com/api/uploads/", Transport: transport})
+
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+		assert.Len(t, versions, 2)
+		assert.Equal(t, "https://uploads.github.example.com/api/uploads/", fetcher.client.UploadURL.String())
+	})
+
+	t.Run("Untrusted certificate", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{Owner: "owner", Repository: "repo", BaseURL: server.URL})
+		_, err := fetcher.FetchTags()
+		assert.ErrorContains(t, err, "certificate")
+	})
+
+	t.Run("Invalid API URL", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{Owner: "owner", Repository: "repo", BaseURL: "://github.example.com"})
+		_, err := fetcher.FetchTags()
+		assert.ErrorContains(t, err, "invalid API URL")
+	})
+}
+
+// newReleasesServer serves the releases of owner/repo, 1.1.0 is the latest
+func newReleasesServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/repos/owner/repo/releases":
+			fmt.Fprint(w, `[
+				{"id":4,"tag_name":"1.2.0","draft":true},
+				{"id":3,"tag_name":"1.2.0-rc.1","prerelease":true,"published_at":"2024-05-02T10:00:00Z"},
+				{"id":2,"tag_name":"1.1.0","published_at":"2024-05-01T10:00:00Z"},
+				{"id":1,"tag_name":"release-1","published_at":"2024-04-01T10:00:00Z"}
+			]`)
+		case "/repos/owner/repo/releases/latest":
+			fmt.Fprint(w, `{"id":2,"tag_name":"1.1.0"}`)
+		case "/repos/owner/empty/releases":
+			fmt.Fprint(w, `[]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGithubClient_FetchReleases(t *testing.T) {
+	server := newReleasesServer(t)
+
+	t.Run("Releases with their flags", func(t *testing.T) {
+		fetcher := newTestFetcher(t, server, &utils.DatasourceConfig{Owner: "owner", Repository: "repo", Kind: datasourceUtils.ReleasesKind})
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+		assert.Len(t, versions, 3)
+		assert.Equal(t, []string{"release-1"}, fetcher.Skipped())
+
+		annotations := map[string]string{}
+		for _, release := range fetcher.Releases() {
+			annotations[release.Version.String()] = release.Annotation()
+		}
+		assert.Equal(t, map[string]string{
+			"1.2.0":      "draft",
+			"1.2.0-rc.1": "prerelease, published 2024-05-02",
+			"1.1.0":      "latest, published 2024-05-01",
+		}, annotations)
+	})
+
+	t.Run("Repository without a latest release", func(t *testing.T) {
+		fetcher := newTestFetcher(t, server, &utils.DatasourceConfig{Owner: "owner", Repository: "empty", Kind: datasourceUtils.ReleasesKind})
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+		assert.Empty(t, versions)
+		assert.Empty(t, fetcher.Releases())
+	})
+}
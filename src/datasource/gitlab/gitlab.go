@@ -1,18 +1,151 @@
 package gitlab
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
 	"src/cmd/smgr/models"
 	"src/cmd/smgr/utils"
+
+	"k8s.io/klog"
+)
+
+const (
+	defaultBaseURL = "https://gitlab.com"
+	tagsPerPage    = 100
+
+	PrivateToken = "private"
+	JobToken     = "job"
 )
 
 type GitlabClient struct {
-	config *utils.DatasourceConfig
+	config     *utils.DatasourceConfig
+	httpClient *http.Client
+	skipped    []string
+}
+
+type tag struct {
+	Name string `json:"name"`
 }
 
 func NewFetcher(config *utils.DatasourceConfig) *GitlabClient {
-	return &GitlabClient{config: config}
+	return &GitlabClient{
+		config:     config,
+		httpClient: datasourceUtils.NewHTTPClient(config),
+	}
 }
 
+// FetchTags lists every tag of the configured project and returns
+// the Semver compliant ones, non compliant tags are recorded as skipped
 func (g *GitlabClient) FetchTags() ([]models.Version, error) {
-	return []models.Version{}, nil
+	project, err := g.projectID()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := g.listTags(project)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, skipped := datasourceUtils.ParseTags(datasourceUtils.NamespaceTags(g.config.Namespace, tags))
+	g.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver tags: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the tags of the last fetch that were not Semver compliant
+func (g *GitlabClient) Skipped() []string {
+	return g.skipped
+}
+
+// projectID returns the numeric ID or the namespaced path of the project.
+// The owner, when set, is the namespace of the repository.
+func (g *GitlabClient) projectID() (string, error) {
+	owner := strings.Trim(g.config.Owner, "/")
+	repo := strings.Trim(g.config.Repository, "/")
+	if repo == "" {
+		return "", errors.New("gitlab: repository is required, either a project ID or a namespaced path")
+	}
+	if owner == "" {
+		return repo, nil
+	}
+	return owner + "/" + repo, nil
+}
+
+func (g *GitlabClient) baseURL() string {
+	if g.config.BaseURL == "" {
+		return defaultBaseURL
+	}
+	return strings.TrimSuffix(g.config.BaseURL, "/")
+}
+
+func (g *GitlabClient) listTags(project string) ([]string, error) {
+	tagList := []string{}
+	nextURL := fmt.Sprintf("%s/api/v4/projects/%s/repository/tags?pagination=keyset&order_by=name&per_page=%d",
+		g.baseURL(), url.PathEscape(project), tagsPerPage)
+
+	for nextURL != "" {
+		req, err := http.NewRequest(http.MethodGet, nextURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("ListTags error: %w", err)
+		}
+		if err := g.authorize(req); err != nil {
+			return nil, err
+		}
+
+		tags, resp, err := g.getTags(req)
+		if err != nil {
+			return nil, datasourceUtils.WrapError("ListTags error", err)
+		}
+
+		for _, tag := range tags {
+			tagList = append(tagList, tag.Name)
+		}
+		nextURL = datasourceUtils.NextLink(resp)
+	}
+
+	return tagList, nil
+}
+
+func (g *GitlabClient) getTags(req *http.Request) ([]tag, *http.Response, error) {
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, resp, fmt.Errorf("GET %s: %s", req.URL.Redacted(), resp.Status)
+	}
+
+	var tags []tag
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, resp, err
+	}
+	return tags, resp, nil
+}
+
+func (g *GitlabClient) authorize(req *http.Request) error {
+	if g.config.Token == "" {
+		return nil
+	}
+
+	switch g.config.TokenType {
+	case PrivateToken, "":
+		req.Header.Set("PRIVATE-TOKEN", g.config.Token)
+	case JobToken:
+		req.Header.Set("JOB-TOKEN", g.config.Token)
+	default:
+		return fmt.Errorf("gitlab: unsupported token type %q, options: %s, %s", g.config.TokenType, PrivateToken, JobToken)
+	}
+	return nil
 }
@@ -1 +1,147 @@
 package gitlab
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"src/cmd/smgr/utils"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves the tags of a single project in keyset pages
+func newTestServer(t *testing.T, project string, pages [][]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/"+project+"/repository/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("PRIVATE-TOKEN") == "" && r.Header.Get("JOB-TOKEN") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("pagination") != "keyset" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		page := 0
+		if token := r.URL.Query().Get("page_token"); token != "" {
+			fmt.Sscanf(token, "%d", &page)
+		}
+		if page+1 < len(pages) {
+			w.Header().Set("Link", fmt.Sprintf(`<http://%s%s?pagination=keyset&order_by=name&per_page=100&page_token=%d>; rel="next"`, r.Host, r.URL.EscapedPath(), page+1))
+		}
+
+		names := []string{}
+		for _, name := range pages[page] {
+			names = append(names, fmt.Sprintf(`{"name":%q}`, name))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, "[%s]", strings.Join(names, ","))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGitlabClient_FetchTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		project     string
+		config      utils.DatasourceConfig
+		pages       [][]string
+		want        []string
+		wantSkipped []string
+	}{
+		{
+			name:    "Project ID",
+			project: "42",
+			config:  utils.DatasourceConfig{Repository: "42", Token: "secret"},
+			pages:   [][]string{{"1.0.0", "1.1.0"}},
+			want:    []string{"1.0.0", "1.1.0"},
+		},
+		{
+			name:    "Namespaced path",
+			project: "group%2Fsubgroup%2Fproject",
+			config:  utils.DatasourceConfig{Repository: "group/subgroup/project", Token: "secret"},
+			pages:   [][]string{{"2.0.0"}},
+			want:    []string{"2.0.0"},
+		},
+		{
+			name:    "Owner as namespace",
+			project: "group%2Fproject",
+			config:  utils.DatasourceConfig{Owner: "group", Repository: "project", Token: "secret"},
+			pages:   [][]string{{"0.1.0"}},
+			want:    []string{"0.1.0"},
+		},
+		{
+			name:        "Job token and keyset pagination",
+			project:     "42",
+			config:      utils.DatasourceConfig{Repository: "42", Token: "job-secret", TokenType: JobToken},
+			pages:       [][]string{{"1.0.0", "release-1.0.1"}, {"1.1.0"}, {"2.0.0-rc.1"}},
+			want:        []string{"1.0.0", "1.1.0", "2.0.0-rc.1"},
+			wantSkipped: []string{"release-1.0.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestServer(t, tt.project, tt.pages)
+			config := tt.config
+			config.BaseURL = server.URL + "/"
+			fetcher := NewFetcher(&config)
+
+			versions, err := fetcher.FetchTags()
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, version := range versions {
+				got = append(got, version.String())
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, fetcher.Skipped())
+		})
+	}
+}
+
+func TestGitlabClient_FetchTagsErrors(t *testing.T) {
+	server := newTestServer(t, "42", [][]string{{"1.0.0"}})
+
+	tests := []struct {
+		name    string
+		config  utils.DatasourceConfig
+		wantErr string
+	}{
+		{
+			name:    "Missing repository",
+			config:  utils.DatasourceConfig{BaseURL: server.URL},
+			wantErr: "repository is required",
+		},
+		{
+			name:    "Unknown project",
+			config:  utils.DatasourceConfig{BaseURL: server.URL, Repository: "43", Token: "secret"},
+			wantErr: "404 Not Found",
+		},
+		{
+			name:    "Missing token",
+			config:  utils.DatasourceConfig{BaseURL: server.URL, Repository: "42"},
+			wantErr: "401 Unauthorized",
+		},
+		{
+			name:    "Unsupported token type",
+			config:  utils.DatasourceConfig{BaseURL: server.URL, Repository: "42", Token: "secret", TokenType: "deploy"},
+			wantErr: "unsupported token type",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFetcher(&tt.config).FetchTags()
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package goproxy
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+
+	"k8s.io/klog"
+)
+
+const defaultProxy = "https://proxy.golang.org"
+
+var pseudoVersionRegex = regexp.MustCompile(`^v[0-9]+\.(0\.0-|\d+\.\d+-([^+]*\.)?0\.)\d{14}-[A-Za-z0-9]+(\+[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+type GoProxyClient struct {
+	config     *utils.DatasourceConfig
+	httpClient *http.Client
+	skipped    []string
+	warnings   []string
+}
+
+func NewFetcher(config *utils.DatasourceConfig) *GoProxyClient {
+	return &GoProxyClient{
+		config:     config,
+		httpClient: datasourceUtils.NewHTTPClient(config),
+	}
+}
+
+// FetchTags lists the versions of the configured module from the @v/list
+// endpoint of a GOPROXY compatible proxy. Pseudo-versions are skipped and a
+// warning is recorded for every major version of 2 or higher that the
+// module path does not carry as a /vN suffix.
+func (g *GoProxyClient) FetchTags() ([]models.Version, error) {
+	module := strings.Trim(g.config.Repository, "/")
+	if module == "" {
+		return nil, errors.New("goproxy: module path is required, e.g. github.com/org/module/v2")
+	}
+
+	rawVersions, err := g.listVersions(module)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	for _, rawVersion := range rawVersions {
+		if IsPseudoVersion(rawVersion) {
+			klog.V(1).Infof("Skipped pseudo-version %s", rawVersion)
+			continue
+		}
+		tags = append(tags, rawVersion)
+	}
+
+	// module versions are always written with the v prefix, which is kept
+	versions, skipped := datasourceUtils.ParseNormalizedTags(tags, func(tag string) string {
+		if !strings.HasPrefix(tag, models.VersionPrefix) {
+			return ""
+		}
+		return tag
+	})
+	g.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver versions: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	g.warnings = majorSuffixWarnings(module, versions)
+	for _, warning := range g.warnings {
+		klog.V(1).Info(warning)
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the versions of the last fetch that were not Semver compliant
+func (g *GoProxyClient) Skipped() []string {
+	return g.skipped
+}
+
+// Warnings returns the major version suffix mismatches of the last fetch
+func (g *GoProxyClient) Warnings() []string {
+	return g.warnings
+}
+
+// IsPseudoVersion reports whether the version is a Go pseudo-version
+// such as v0.0.0-20191109021931-daa7c04131f5
+func IsPseudoVersion(version string) bool {
+	return strings.Count(version, "-") >= 2 && pseudoVersionRegex.MatchString(version)
+}
+
+// EscapePath escapes the upper case letters of a module path as the
+// proxy protocol requires, e.g. github.com/Azure becomes github.com/!azure
+func EscapePath(module string) string {
+	var builder strings.Builder
+	for _, r := range module {
+		if unicode.IsUpper(r) {
+			builder.WriteRune('!')
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+// MajorSuffix returns the major version carried by the module path,
+// github.com/org/module/v2 carries 2 and gopkg.in/yaml.v3 carries 3
+func MajorSuffix(module string) (uint64, bool) {
+	last := module[strings.LastIndex(module, "/")+1:]
+	if strings.HasPrefix(module, "gopkg.in/") {
+		if index := strings.LastIndex(last, ".v"); index >= 0 {
+			last = last[index+1:]
+		}
+	}
+
+	if len(last) < 2 || last[0] != 'v' || last[1] == '0' {
+		return 0, false
+	}
+	major, err := strconv.ParseUint(last[1:], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	if major < 2 && !strings.HasPrefix(module, "gopkg.in/") {
+		return 0, false
+	}
+	return major, true
+}
+
+func majorSuffixWarnings(module string, versions []models.Version) []string {
+	suffix, hasSuffix := MajorSuffix(module)
+	mismatches := map[uint64]bool{}
+
+	for _, version := range versions {
+		major := version.Release.Major.Value()
+		if version.BuildMetadata.String() == "+incompatible" {
+			continue
+		}
+		if hasSuffix && major != suffix || !hasSuffix && major >= 2 {
+			mismatches[major] = true
+		}
+	}
+
+	majors := []uint64{}
+	for major := range mismatches {
+		majors = append(majors, major)
+	}
+	sort.Slice(majors, func(i, j int) bool { return majors[i] < majors[j] })
+
+	warnings := []string{}
+	for _, major := range majors {
+		if hasSuffix {
+			warnings = append(warnings, fmt.Sprintf("module %s carries major version v%d but has v%d versions", module, suffix, major))
+		} else {
+			warnings = append(warnings, fmt.Sprintf("module %s has v%d versions without a matching /v%d module path", module, major, major))
+		}
+	}
+	return warnings
+}
+
+// proxy returns the configured base URL, the first proxy of GOPROXY,
+// or the public Go module proxy
+func (g *GoProxyClient) proxy() string {
+	if g.config.BaseURL != "" {
+		return strings.TrimSuffix(g.config.BaseURL, "/")
+	}
+
+	for _, entry := range strings.FieldsFunc(os.Getenv("GOPROXY"), func(r rune) bool { return r == ',' || r == '|' }) {
+		if strings.HasPrefix(entry, "https://") || strings.HasPrefix(entry, "http://") {
+			return strings.TrimSuffix(entry, "/")
+		}
+	}
+	return defaultProxy
+}
+
+func (g *GoProxyClient) listVersions(module string) ([]string, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/%s/@v/list", g.proxy(), EscapePath(module)), nil)
+	if err != nil {
+		return nil, fmt.Errorf("goproxy: list error: %w", err)
+	}
+	if g.config.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+g.config.Token)
+	}
+
+	resp, err := g.httpClient.Do(req)
+	if err != nil {
+		return nil, datasourceUtils.WrapError("goproxy: list error", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("goproxy: list error: GET %s: %s", req.URL.Redacted(), resp.Status)
+	}
+
+	rawVersions := []string{}
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if rawVersion := strings.TrimSpace(scanner.Text()); rawVersion != "" {
+			rawVersions = append(rawVersions, rawVersion)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("goproxy: list error: %w", err)
+	}
+	return rawVersions, nil
+}
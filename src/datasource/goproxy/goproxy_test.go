@@ -0,0 +1,145 @@
+package goproxy
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"src/cmd/smgr/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProxy(t *testing.T, modules map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		list, found := modules[r.URL.Path]
+		if !found {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, list)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGoProxyClient_FetchTags(t *testing.T) {
+	server := newTestProxy(t, map[string]string{
+		"/github.com/org/module/@v/list":    "v0.1.0\nv1.0.0\nv1.1.0-rc.1\nv0.0.0-20191109021931-daa7c04131f5\nv1.2.1-0.20200101000000-abcdefabcdef\n",
+		"/github.com/org/module/v2/@v/list": "v2.0.0\nv2.1.0\n",
+		"/github.com/org/legacy/@v/list":    "v1.0.0\nv2.0.0+incompatible\nv3.0.0\nv4.1.0\n",
+		"/github.com/!azure/sdk/@v/list":    "v1.0.0\n1.1.0\n",
+	})
+
+	tests := []struct {
+		name         string
+		module       string
+		want         []string
+		wantSkipped  []string
+		wantWarnings []string
+	}{
+		{
+			name:         "Pseudo-versions are skipped",
+			module:       "github.com/org/module",
+			want:         []string{"v0.1.0", "v1.0.0", "v1.1.0-rc.1"},
+			wantWarnings: []string{},
+		},
+		{
+			name:         "Major version suffix",
+			module:       "github.com/org/module/v2",
+			want:         []string{"v2.0.0", "v2.1.0"},
+			wantWarnings: []string{},
+		},
+		{
+			name:   "Major versions without module path suffix",
+			module: "github.com/org/legacy",
+			want:   []string{"v1.0.0", "v2.0.0+incompatible", "v3.0.0", "v4.1.0"},
+			wantWarnings: []string{
+				"module github.com/org/legacy has v3 versions without a matching /v3 module path",
+				"module github.com/org/legacy has v4 versions without a matching /v4 module path",
+			},
+		},
+		{
+			name:         "Escaped module path and versions without v",
+			module:       "github.com/Azure/sdk",
+			want:         []string{"v1.0.0"},
+			wantSkipped:  []string{"1.1.0"},
+			wantWarnings: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: tt.module})
+			versions, err := fetcher.FetchTags()
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, version := range versions {
+				got = append(got, version.String())
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, fetcher.Skipped())
+			assert.Equal(t, tt.wantWarnings, fetcher.Warnings())
+		})
+	}
+
+	t.Run("Unknown module", func(t *testing.T) {
+		_, err := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "github.com/org/unknown"}).FetchTags()
+		assert.ErrorContains(t, err, "404 Not Found")
+	})
+
+	t.Run("Missing module", func(t *testing.T) {
+		_, err := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL}).FetchTags()
+		assert.Error(t, err)
+	})
+
+	t.Run("Proxy from GOPROXY", func(t *testing.T) {
+		t.Setenv("GOPROXY", "off,"+server.URL+"|direct")
+		fetcher := NewFetcher(&utils.DatasourceConfig{Repository: "github.com/org/module/v2"})
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+		assert.Len(t, versions, 2)
+	})
+}
+
+func TestIsPseudoVersion(t *testing.T) {
+	assert.True(t, IsPseudoVersion("v0.0.0-20191109021931-daa7c04131f5"))
+	assert.True(t, IsPseudoVersion("v1.2.4-0.20191109021931-daa7c04131f5"))
+	assert.True(t, IsPseudoVersion("v1.2.3-pre.0.20191109021931-daa7c04131f5"))
+	assert.True(t, IsPseudoVersion("v2.0.0-20191109021931-daa7c04131f5+incompatible"))
+	assert.False(t, IsPseudoVersion("v1.2.3"))
+	assert.False(t, IsPseudoVersion("v1.2.3-rc.1"))
+	assert.False(t, IsPseudoVersion("v1.2.3-alpha-beta"))
+}
+
+func TestMajorSuffix(t *testing.T) {
+	tests := []struct {
+		module    string
+		want      uint64
+		wantFound bool
+	}{
+		{module: "github.com/org/module", wantFound: false},
+		{module: "github.com/org/module/v2", want: 2, wantFound: true},
+		{module: "github.com/org/module/v10", want: 10, wantFound: true},
+		{module: "github.com/org/module/v1", wantFound: false},
+		{module: "github.com/org/module/version", wantFound: false},
+		{module: "gopkg.in/yaml.v3", want: 3, wantFound: true},
+		{module: "gopkg.in/yaml.v1", want: 1, wantFound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.module, func(t *testing.T) {
+			got, found := MajorSuffix(tt.module)
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEscapePath(t *testing.T) {
+	assert.Equal(t, "github.com/!azure/!a!p!i", EscapePath("github.com/Azure/API"))
+	assert.Equal(t, "github.com/org/module", EscapePath("github.com/org/module"))
+}
@@ -0,0 +1,125 @@
+package helm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/klog"
+)
+
+type HelmClient struct {
+	config     *utils.DatasourceConfig
+	httpClient *http.Client
+	skipped    []string
+}
+
+// index is the part of a repository index.yaml the client reads
+type index struct {
+	Entries map[string][]struct {
+		Version    string `yaml:"version"`
+		AppVersion string `yaml:"appVersion"`
+	} `yaml:"entries"`
+}
+
+func NewFetcher(config *utils.DatasourceConfig) *HelmClient {
+	return &HelmClient{
+		config:     config,
+		httpClient: datasourceUtils.NewHTTPClient(config),
+	}
+}
+
+// FetchTags reads the versions of the configured chart from the index.yaml
+// of a classic HTTP chart repository. When AppVersion is set the distinct
+// appVersion values of the chart are returned instead.
+func (h *HelmClient) FetchTags() ([]models.Version, error) {
+	chart := strings.TrimSpace(h.config.Chart)
+	if chart == "" {
+		return nil, errors.New("helm: chart name is required")
+	}
+
+	doc, err := h.getIndex()
+	if err != nil {
+		return nil, err
+	}
+	entries, found := doc.Entries[chart]
+	if !found {
+		return nil, fmt.Errorf("helm: chart %s not found in %s", chart, h.indexURL())
+	}
+
+	rawVersions := []string{}
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		rawVersion := entry.Version
+		if h.config.AppVersion {
+			rawVersion = entry.AppVersion
+		}
+		if rawVersion == "" || seen[rawVersion] {
+			continue
+		}
+		seen[rawVersion] = true
+		rawVersions = append(rawVersions, rawVersion)
+	}
+
+	versions, skipped := datasourceUtils.ParseTags(rawVersions)
+	h.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver versions: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the versions of the last fetch that were not Semver compliant
+func (h *HelmClient) Skipped() []string {
+	return h.skipped
+}
+
+// indexURL returns the address of the index.yaml, the repository is either
+// the chart repository URL or the index file itself
+func (h *HelmClient) indexURL() string {
+	repository := h.config.Repository
+	if repository == "" {
+		repository = h.config.BaseURL
+	}
+	repository = strings.TrimSuffix(strings.TrimSpace(repository), "/")
+	if strings.HasSuffix(repository, ".yaml") || strings.HasSuffix(repository, ".yml") {
+		return repository
+	}
+	return repository + "/index.yaml"
+}
+
+func (h *HelmClient) getIndex() (*index, error) {
+	if h.config.Repository == "" && h.config.BaseURL == "" {
+		return nil, errors.New("helm: repository URL is required, e.g. https://charts.example.com")
+	}
+
+	req, err := http.NewRequest(http.MethodGet, h.indexURL(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("helm: index error: %w", err)
+	}
+	if h.config.Token != "" {
+		req.SetBasicAuth(h.config.Username, h.config.Token)
+	}
+
+	resp, err := h.httpClient.Do(req)
+	if err != nil {
+		return nil, datasourceUtils.WrapError("helm: index error", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("helm: index error: GET %s: %s", req.URL.Redacted(), resp.Status)
+	}
+
+	doc := &index{}
+	if err := yaml.NewDecoder(resp.Body).Decode(doc); err != nil {
+		return nil, fmt.Errorf("helm: index error: %w", err)
+	}
+	return doc, nil
+}
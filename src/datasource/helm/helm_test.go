@@ -0,0 +1,97 @@
+package helm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"src/cmd/smgr/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIndex = `apiVersion: v1
+entries:
+  mychart:
+    - name: mychart
+      version: 1.2.0
+      appVersion: "2.1.0"
+    - name: mychart
+      version: 1.1.0
+      appVersion: v2.1.0-rc.1
+    - name: mychart
+      version: 1.0.1
+      appVersion: "2.1.0"
+    - name: mychart
+      version: v1.0.0
+      appVersion: latest
+  other:
+    - name: other
+      version: 0.1.0
+generated: "2024-01-01T00:00:00Z"
+`
+
+func versionStrings(t *testing.T, fetcher *HelmClient) []string {
+	t.Helper()
+	versions, err := fetcher.FetchTags()
+	require.NoError(t, err)
+
+	got := []string{}
+	for _, version := range versions {
+		got = append(got, version.String())
+	}
+	return got
+}
+
+func TestHelmClient_FetchTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/charts/index.yaml":
+			fmt.Fprint(w, testIndex)
+		case "/private/index.yaml":
+			if username, password, _ := r.BasicAuth(); username != "user" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, testIndex)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("Chart versions", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{Repository: server.URL + "/charts/", Chart: "mychart"})
+		assert.Equal(t, []string{"1.2.0", "1.1.0", "1.0.1", "v1.0.0"}, versionStrings(t, fetcher))
+	})
+
+	t.Run("App versions", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{Repository: server.URL + "/charts", Chart: "mychart", AppVersion: true})
+		assert.Equal(t, []string{"2.1.0", "v2.1.0-rc.1"}, versionStrings(t, fetcher))
+		assert.Equal(t, []string{"latest"}, fetcher.Skipped())
+	})
+
+	t.Run("Index URL with credentials", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{Repository: server.URL + "/private/index.yaml", Chart: "other", Username: "user", Token: "secret"})
+		assert.Equal(t, []string{"0.1.0"}, versionStrings(t, fetcher))
+	})
+
+	t.Run("Unknown chart", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{Repository: server.URL + "/charts", Chart: "unknown"})
+		_, err := fetcher.FetchTags()
+		assert.ErrorContains(t, err, "chart unknown not found")
+	})
+
+	t.Run("Missing chart", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{Repository: server.URL + "/charts"})
+		_, err := fetcher.FetchTags()
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown repository", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{Repository: server.URL, Chart: "mychart"})
+		_, err := fetcher.FetchTags()
+		assert.ErrorContains(t, err, "404 Not Found")
+	})
+}
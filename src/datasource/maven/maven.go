@@ -0,0 +1,211 @@
+package maven
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+
+	"k8s.io/klog"
+)
+
+const defaultRepository = "https://repo1.maven.org/maven2"
+
+var mavenVersionRegex = regexp.MustCompile(`^(\d+)(?:\.(\d+))?(?:\.(\d+))?(?:[-.]?([A-Za-z][0-9A-Za-z.-]*|\d+[A-Za-z][0-9A-Za-z.-]*))?$`)
+
+// releaseQualifiers mark a release, sp is a service pack released after it
+var releaseQualifiers = map[string]bool{"": true, "final": true, "ga": true, "release": true}
+
+// prereleaseQualifiers are ordered before the release as Maven does, the
+// other qualifiers such as jre or android are ordered after it
+var prereleaseQualifiers = map[string]bool{"alpha": true, "beta": true, "milestone": true, "rc": true, "snapshot": true}
+
+var qualifierAliases = map[string]string{"a": "alpha", "b": "beta", "m": "milestone", "cr": "rc"}
+
+type MavenClient struct {
+	config     *utils.DatasourceConfig
+	httpClient *http.Client
+	skipped    []string
+}
+
+type metadata struct {
+	Versioning struct {
+		Versions []string `xml:"versions>version"`
+	} `xml:"versioning"`
+}
+
+func NewFetcher(config *utils.DatasourceConfig) *MavenClient {
+	return &MavenClient{
+		config:     config,
+		httpClient: datasourceUtils.NewHTTPClient(config),
+	}
+}
+
+// FetchTags reads the maven-metadata.xml of the configured artifact and
+// normalises its versions into Semver versions, see Normalize
+func (m *MavenClient) FetchTags() ([]models.Version, error) {
+	groupID, artifactID, err := m.coordinates()
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := m.getMetadata(groupID, artifactID)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, skipped := datasourceUtils.ParseNormalizedTags(doc.Versioning.Versions, Normalize)
+	m.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver versions: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the versions of the last fetch that did not map to Semver
+func (m *MavenClient) Skipped() []string {
+	return m.skipped
+}
+
+// Warnings lists the versions of the last fetch that did not map to Semver
+func (m *MavenClient) Warnings() []string {
+	return datasourceUtils.UnmappedWarnings("maven", m.skipped)
+}
+
+// Normalize maps a Maven version to a Semver version when the precedence
+// can be kept: the release is padded to three digits and the qualifier is
+// split into lower cased prerelease identifiers, e.g. 2.3-RC1 becomes
+// 2.3.0-rc.1. Final, GA and RELEASE qualifiers are releases. Service packs,
+// qualifiers Maven orders after the release e.g. 31.1-jre and releases of
+// more than three digits do not map, an empty string is returned for them.
+func Normalize(version string) string {
+	matches := mavenVersionRegex.FindStringSubmatch(strings.TrimSpace(version))
+	if matches == nil {
+		return ""
+	}
+
+	digits := []string{}
+	for _, digit := range matches[1:4] {
+		if digit == "" {
+			digit = "0"
+		}
+		value, err := strconv.ParseUint(digit, 10, 64)
+		if err != nil {
+			return ""
+		}
+		digits = append(digits, strconv.FormatUint(value, 10))
+	}
+	normalized := strings.Join(digits, ".")
+
+	qualifier := strings.ToLower(matches[4])
+	if releaseQualifiers[qualifier] {
+		return normalized
+	}
+
+	identifiers := splitQualifier(qualifier)
+	if len(identifiers) == 0 || identifiers[0] == "sp" || containsOnlyDigits(identifiers[0]) {
+		return ""
+	}
+	if len(identifiers) > 1 && containsOnlyDigits(identifiers[1]) {
+		if alias, found := qualifierAliases[identifiers[0]]; found {
+			identifiers[0] = alias
+		}
+	}
+	if !prereleaseQualifiers[identifiers[0]] {
+		return ""
+	}
+	return normalized + "-" + strings.Join(identifiers, ".")
+}
+
+// splitQualifier splits a qualifier on dots, hyphens and transitions
+// between letters and digits, as Maven compares them
+func splitQualifier(qualifier string) []string {
+	identifiers := []string{}
+	current := []rune{}
+	flush := func() {
+		if len(current) == 0 {
+			return
+		}
+		identifier := string(current)
+		if containsOnlyDigits(identifier) {
+			value, err := strconv.ParseUint(identifier, 10, 64)
+			if err == nil {
+				identifier = strconv.FormatUint(value, 10)
+			}
+		}
+		identifiers = append(identifiers, identifier)
+		current = []rune{}
+	}
+
+	for _, r := range qualifier {
+		if r == '.' || r == '-' {
+			flush()
+			continue
+		}
+		if len(current) > 0 && unicode.IsDigit(r) != unicode.IsDigit(current[len(current)-1]) {
+			flush()
+		}
+		current = append(current, r)
+	}
+	flush()
+	return identifiers
+}
+
+func containsOnlyDigits(s string) bool {
+	return s != "" && strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) }) == -1
+}
+
+// coordinates returns the group and artifact IDs, either from a
+// groupId:artifactId repository or from the owner and the repository
+func (m *MavenClient) coordinates() (groupID string, artifactID string, err error) {
+	groupID, artifactID = strings.TrimSpace(m.config.Owner), strings.TrimSpace(m.config.Repository)
+	if group, artifact, found := strings.Cut(artifactID, ":"); found {
+		groupID, artifactID = group, artifact
+	}
+	if groupID == "" || artifactID == "" {
+		return "", "", errors.New("maven: group and artifact IDs are required, e.g. org.example:app")
+	}
+	return groupID, artifactID, nil
+}
+
+func (m *MavenClient) baseURL() string {
+	if m.config.BaseURL == "" {
+		return defaultRepository
+	}
+	return strings.TrimSuffix(m.config.BaseURL, "/")
+}
+
+func (m *MavenClient) getMetadata(groupID, artifactID string) (*metadata, error) {
+	rawURL := fmt.Sprintf("%s/%s/%s/maven-metadata.xml", m.baseURL(), strings.ReplaceAll(groupID, ".", "/"), artifactID)
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("maven: metadata error: %w", err)
+	}
+	if m.config.Token != "" {
+		req.SetBasicAuth(m.config.Username, m.config.Token)
+	}
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return nil, datasourceUtils.WrapError("maven: metadata error", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("maven: metadata error: GET %s: %s", req.URL.Redacted(), resp.Status)
+	}
+
+	doc := &metadata{}
+	if err := xml.NewDecoder(resp.Body).Decode(doc); err != nil {
+		return nil, fmt.Errorf("maven: metadata error: %w", err)
+	}
+	return doc, nil
+}
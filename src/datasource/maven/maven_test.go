@@ -0,0 +1,128 @@
+package maven
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"src/cmd/smgr/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"1.2", "1.2.0"},
+		{"3", "3.0.0"},
+		{"2.3-RC1", "2.3.0-rc.1"},
+		{"1.0.0-M2", "1.0.0-milestone.2"},
+		{"1.0a1", "1.0.0-alpha.1"},
+		{"1.0-beta-2", "1.0.0-beta.2"},
+		{"1.0-CR1", "1.0.0-rc.1"},
+		{"1.0-SNAPSHOT", "1.0.0-snapshot"},
+		{"5.3.0.Final", "5.3.0"},
+		{"1.0-GA", "1.0.0"},
+		{"1.0.RELEASE", "1.0.0"},
+		{"1.0-SP1", ""},
+		{"31.1-jre", ""},
+		{"31.1-android", ""},
+		{"1.0-preview", ""},
+		{"1.2.3.4", ""},
+		{"1.2.3-4", ""},
+		{"latest", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Normalize(tt.version))
+		})
+	}
+}
+
+func TestMavenClient_FetchTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/org/example/app/maven-metadata.xml":
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?>
+<metadata>
+  <groupId>org.example</groupId>
+  <artifactId>app</artifactId>
+  <versioning>
+    <latest>2.0.0-RC1</latest>
+    <release>1.1</release>
+    <versions>
+      <version>1.0</version>
+      <version>1.1</version>
+      <version>2.0.0-RC1</version>
+      <version>1.1-SP1</version>
+    </versions>
+  </versioning>
+</metadata>`)
+		case "/com/private/lib/maven-metadata.xml":
+			if username, password, _ := r.BasicAuth(); username != "deploy" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `<metadata><versioning><versions><version>0.1.0</version></versions></versioning></metadata>`)
+		case "/com/google/guava/guava/maven-metadata.xml":
+			fmt.Fprint(w, `<metadata><versioning><versions>
+  <version>31.0-rc1</version>
+  <version>31.0-jre</version>
+  <version>31.0-android</version>
+  <version>31.1-jre</version>
+</versions></versioning></metadata>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("Coordinates as repository", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL + "/", Repository: "org.example:app"})
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+
+		got := []string{}
+		for _, version := range versions {
+			got = append(got, version.String())
+		}
+		assert.Equal(t, []string{"1.0.0", "1.1.0", "2.0.0-rc.1"}, got)
+		assert.Equal(t, []string{"maven: 1 versions do not map to Semver: 1.1-SP1"}, fetcher.Warnings())
+	})
+
+	t.Run("Qualifiers ordered after the release", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "com.google.guava:guava"})
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+
+		got := []string{}
+		for _, version := range versions {
+			got = append(got, version.String())
+		}
+		assert.Equal(t, []string{"31.0.0-rc.1"}, got)
+		assert.Equal(t, []string{"maven: 3 versions do not map to Semver: 31.0-jre 31.0-android 31.1-jre"}, fetcher.Warnings())
+	})
+
+	t.Run("Group as owner with token", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Owner: "com.private", Repository: "lib", Username: "deploy", Token: "secret"})
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+		assert.Len(t, versions, 1)
+	})
+
+	t.Run("Missing group", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "app"})
+		_, err := fetcher.FetchTags()
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown artifact", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "org.example:unknown"})
+		_, err := fetcher.FetchTags()
+		assert.ErrorContains(t, err, "404 Not Found")
+	})
+}
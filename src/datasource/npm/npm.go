@@ -0,0 +1,166 @@
+package npm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+
+	"k8s.io/klog"
+)
+
+const (
+	defaultRegistry = "https://registry.npmjs.org"
+	// abbreviatedPackument only carries what installers need, versions and dist-tags included
+	abbreviatedPackument = "application/vnd.npm.install-v1+json; q=1.0, application/json; q=0.8"
+)
+
+type NpmClient struct {
+	config     *utils.DatasourceConfig
+	httpClient *http.Client
+	npmrcPaths []string
+	distTags   map[string]models.Version
+	skipped    []string
+}
+
+type packument struct {
+	Name     string                     `json:"name"`
+	DistTags map[string]string          `json:"dist-tags"`
+	Versions map[string]json.RawMessage `json:"versions"`
+}
+
+func NewFetcher(config *utils.DatasourceConfig) *NpmClient {
+	return &NpmClient{
+		config:     config,
+		httpClient: datasourceUtils.NewHTTPClient(config),
+		npmrcPaths: defaultNpmrcPaths(),
+	}
+}
+
+// FetchTags reads the packument of the configured package and returns its
+// published Semver versions. The registry and the token default to the
+// .npmrc settings of the project and of the user.
+func (n *NpmClient) FetchTags() ([]models.Version, error) {
+	name, scope, err := n.packageName()
+	if err != nil {
+		return nil, err
+	}
+
+	settings := loadNpmrc(n.npmrcPaths)
+	registry := n.registry(settings, scope)
+	doc, err := n.getPackument(registry, name, n.token(settings, registry))
+	if err != nil {
+		return nil, err
+	}
+
+	rawVersions := make([]string, 0, len(doc.Versions))
+	for rawVersion := range doc.Versions {
+		rawVersions = append(rawVersions, rawVersion)
+	}
+	sort.Strings(rawVersions)
+
+	versions, skipped := datasourceUtils.ParseTags(rawVersions)
+	n.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver versions: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	n.distTags = map[string]models.Version{}
+	for distTag, rawVersion := range doc.DistTags {
+		if version, err := models.ParseVersion(rawVersion); err == nil {
+			n.distTags[distTag] = version
+		}
+	}
+
+	return versions, nil
+}
+
+// Token returns the token the packument of the configured package is read
+// with, the configured token or the .npmrc token of its registry
+func Token(config *utils.DatasourceConfig) string {
+	n := &NpmClient{config: config, npmrcPaths: defaultNpmrcPaths()}
+	_, scope, err := n.packageName()
+	if err != nil {
+		return config.Token
+	}
+	settings := loadNpmrc(n.npmrcPaths)
+	return n.token(settings, n.registry(settings, scope))
+}
+
+// Skipped returns the versions of the last fetch that were not Semver compliant
+func (n *NpmClient) Skipped() []string {
+	return n.skipped
+}
+
+// DistTags returns the dist-tags of the last fetch, e.g. latest or next
+func (n *NpmClient) DistTags() map[string]models.Version {
+	return n.distTags
+}
+
+// packageName returns the package name and its scope, the owner
+// when set is the scope of the repository e.g. @org or org
+func (n *NpmClient) packageName() (name string, scope string, err error) {
+	name = strings.TrimSpace(n.config.Repository)
+	if owner := strings.TrimSpace(n.config.Owner); owner != "" {
+		name = "@" + strings.TrimPrefix(owner, "@") + "/" + name
+	}
+	if name == "" {
+		return "", "", errors.New("npm: package name is required, e.g. left-pad or @scope/name")
+	}
+
+	if strings.HasPrefix(name, "@") {
+		scope, _, _ = strings.Cut(name, "/")
+	}
+	return name, scope, nil
+}
+
+func (n *NpmClient) registry(settings npmrc, scope string) string {
+	registry := n.config.BaseURL
+	if registry == "" {
+		registry = settings.registry(scope)
+	}
+	if registry == "" {
+		registry = defaultRegistry
+	}
+	return strings.TrimSuffix(registry, "/")
+}
+
+func (n *NpmClient) token(settings npmrc, registry string) string {
+	if n.config.Token != "" {
+		return n.config.Token
+	}
+	return settings.authToken(registry)
+}
+
+func (n *NpmClient) getPackument(registry, name, token string) (*packument, error) {
+	req, err := http.NewRequest(http.MethodGet, registry+"/"+url.PathEscape(name), nil)
+	if err != nil {
+		return nil, fmt.Errorf("npm: packument error: %w", err)
+	}
+	req.Header.Set("Accept", abbreviatedPackument)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return nil, datasourceUtils.WrapError("npm: packument error", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("npm: packument error: GET %s: %s", req.URL.Redacted(), resp.Status)
+	}
+
+	doc := &packument{}
+	if err := json.NewDecoder(resp.Body).Decode(doc); err != nil {
+		return nil, fmt.Errorf("npm: packument error: %w", err)
+	}
+	return doc, nil
+}
@@ -0,0 +1,145 @@
+package npm
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"src/cmd/smgr/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRegistry serves the packuments of left-pad and of the private
+// @team/app, which requires the "secret" token
+func newTestRegistry(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/vnd.npm.install-v1+json")
+		switch r.URL.EscapedPath() {
+		case "/left-pad":
+			fmt.Fprint(w, `{"name":"left-pad","dist-tags":{"latest":"1.3.0"},"versions":{"1.3.0":{},"1.1.0":{},"0.0.3":{}}}`)
+		case "/@team%2Fapp":
+			if r.Header.Get("Authorization") != "Bearer secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"name":"@team/app","dist-tags":{"latest":"2.0.0","next":"3.0.0-beta.1"},"versions":{"2.0.0":{},"3.0.0-beta.1":{},"1.0":{}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func versionStrings(t *testing.T, fetcher *NpmClient) []string {
+	t.Helper()
+	versions, err := fetcher.FetchTags()
+	require.NoError(t, err)
+
+	got := []string{}
+	for _, version := range versions {
+		got = append(got, version.String())
+	}
+	return got
+}
+
+func TestNpmClient_FetchTags(t *testing.T) {
+	server := newTestRegistry(t)
+
+	t.Run("Unscoped package", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "left-pad"})
+		fetcher.npmrcPaths = nil
+		assert.Equal(t, []string{"0.0.3", "1.1.0", "1.3.0"}, versionStrings(t, fetcher))
+
+		latest := fetcher.DistTags()["latest"]
+		assert.Equal(t, "1.3.0", latest.String())
+	})
+
+	t.Run("Scoped package with token", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL + "/", Repository: "@team/app", Token: "secret"})
+		fetcher.npmrcPaths = nil
+		assert.Equal(t, []string{"2.0.0", "3.0.0-beta.1"}, versionStrings(t, fetcher))
+		assert.Equal(t, []string{"1.0"}, fetcher.Skipped())
+
+		next := fetcher.DistTags()["next"]
+		assert.Equal(t, "3.0.0-beta.1", next.String())
+	})
+
+	t.Run("Scope as owner", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Owner: "@team", Repository: "app", Token: "secret"})
+		fetcher.npmrcPaths = nil
+		assert.Equal(t, []string{"2.0.0", "3.0.0-beta.1"}, versionStrings(t, fetcher))
+	})
+
+	t.Run("Registry and token from npmrc", func(t *testing.T) {
+		t.Setenv("TEST_NPM_TOKEN", "secret")
+		npmrcPath := filepath.Join(t.TempDir(), ".npmrc")
+		content := fmt.Sprintf("@team:registry=%s/\n//%s/:_authToken=${TEST_NPM_TOKEN}\n", server.URL, server.Listener.Addr().String())
+		require.NoError(t, os.WriteFile(npmrcPath, []byte(content), 0o600))
+
+		fetcher := NewFetcher(&utils.DatasourceConfig{Repository: "@team/app"})
+		fetcher.npmrcPaths = []string{npmrcPath}
+		assert.Equal(t, []string{"2.0.0", "3.0.0-beta.1"}, versionStrings(t, fetcher))
+	})
+}
+
+func TestNpmClient_FetchTagsErrors(t *testing.T) {
+	server := newTestRegistry(t)
+
+	tests := []struct {
+		name    string
+		config  utils.DatasourceConfig
+		wantErr string
+	}{
+		{
+			name:    "Missing package name",
+			config:  utils.DatasourceConfig{BaseURL: server.URL},
+			wantErr: "package name is required",
+		},
+		{
+			name:    "Unknown package",
+			config:  utils.DatasourceConfig{BaseURL: server.URL, Repository: "unknown"},
+			wantErr: "404 Not Found",
+		},
+		{
+			name:    "Private package without token",
+			config:  utils.DatasourceConfig{BaseURL: server.URL, Repository: "@team/app"},
+			wantErr: "401 Unauthorized",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := NewFetcher(&tt.config)
+			fetcher.npmrcPaths = nil
+			_, err := fetcher.FetchTags()
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestNpmrc_authToken(t *testing.T) {
+	settings := npmrc{
+		"//registry.npmjs.org/:_authToken":         "public",
+		"//npm.example.com/:_authToken":            "example",
+		"//npm.example.com/team/:_authToken":       "team",
+		"//npm.example.com/team/nested:_authToken": "nested",
+		"registry":        "https://npm.example.com/team/",
+		"@other:registry": "https://registry.npmjs.org/",
+	}
+
+	assert.Equal(t, "public", settings.authToken("https://registry.npmjs.org"))
+	assert.Equal(t, "example", settings.authToken("https://npm.example.com"))
+	assert.Equal(t, "team", settings.authToken("https://npm.example.com/team"))
+	assert.Equal(t, "nested", settings.authToken("https://npm.example.com/team/nested/"))
+	assert.Equal(t, "", settings.authToken("https://unknown.example.com"))
+
+	assert.Equal(t, "https://npm.example.com/team/", settings.registry(""))
+	assert.Equal(t, "https://registry.npmjs.org/", settings.registry("@other"))
+	assert.Equal(t, "https://npm.example.com/team/", settings.registry("@team"))
+}
@@ -0,0 +1,86 @@
+package npm
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// npmrc holds the settings of .npmrc files that matter to read a packument
+type npmrc map[string]string
+
+// defaultNpmrcPaths returns the project then the user .npmrc,
+// the first file setting a key wins
+func defaultNpmrcPaths() []string {
+	paths := []string{".npmrc"}
+	if userConfig := os.Getenv("NPM_CONFIG_USERCONFIG"); userConfig != "" {
+		paths = append(paths, userConfig)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".npmrc"))
+	}
+	return paths
+}
+
+// loadNpmrc reads the key=value lines of the files, expanding ${VAR}
+// environment references the way npm does
+func loadNpmrc(paths []string) npmrc {
+	settings := npmrc{}
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+				continue
+			}
+			key, value, found := strings.Cut(line, "=")
+			if !found {
+				continue
+			}
+			key = strings.TrimSpace(key)
+			if _, set := settings[key]; !set {
+				settings[key] = os.ExpandEnv(strings.Trim(strings.TrimSpace(value), `"'`))
+			}
+		}
+		file.Close()
+	}
+	return settings
+}
+
+// registry returns the registry configured for the scope of the package
+// or the default registry
+func (n npmrc) registry(scope string) string {
+	if scope != "" && n[scope+":registry"] != "" {
+		return n[scope+":registry"]
+	}
+	return n["registry"]
+}
+
+// authToken returns the _authToken of the most specific
+// //host/path/:_authToken entry matching the registry
+func (n npmrc) authToken(registry string) string {
+	nerfed := registry
+	if _, rest, found := strings.Cut(registry, "://"); found {
+		nerfed = "//" + rest
+	}
+	nerfed = strings.TrimSuffix(nerfed, "/") + "/"
+
+	token := ""
+	longest := 0
+	for key, value := range n {
+		prefix, found := strings.CutSuffix(key, ":_authToken")
+		if !found {
+			continue
+		}
+		prefix = strings.TrimSuffix(prefix, "/") + "/"
+		if strings.HasPrefix(nerfed, prefix) && len(prefix) > longest {
+			token, longest = value, len(prefix)
+		}
+	}
+	return token
+}
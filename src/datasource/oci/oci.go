@@ -1,18 +1,261 @@
 package oci
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
 	"src/cmd/smgr/models"
 	"src/cmd/smgr/utils"
+
+	"k8s.io/klog"
+)
+
+const (
+	tagsPerPage     = 100
+	defaultUsername = "smgr"
+
+	dockerHub         = "docker.io"
+	dockerHubRegistry = "registry-1.docker.io"
 )
 
-type OciCLient struct {
-	config *utils.DatasourceConfig
+type OciClient struct {
+	config        *utils.DatasourceConfig
+	httpClient    *http.Client
+	registry      *url.URL
+	authorization string
+	skipped       []string
+}
+
+type tagList struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
 }
 
-func NewFetcher(config *utils.DatasourceConfig) *OciCLient {
-	return &OciCLient{config: config}
+func NewFetcher(config *utils.DatasourceConfig) *OciClient {
+	return &OciClient{
+		config:     config,
+		httpClient: datasourceUtils.NewHTTPClient(config),
+	}
+}
+
+// FetchTags lists every tag of the configured repository through the
+// distribution tags/list endpoint and returns the Semver compliant ones.
+// Build metadata written with the registry safe "_" is read back as "+".
+func (o *OciClient) FetchTags() ([]models.Version, error) {
+	registry, name, err := o.reference()
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := o.listTags(registry, name)
+	if err != nil {
+		return nil, err
+	}
+
+	versions, skipped := datasourceUtils.ParseNormalizedTags(datasourceUtils.NamespaceTags(o.config.Namespace, tags), FromRegistryTag)
+	o.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver tags: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the tags of the last fetch that were not Semver compliant
+func (o *OciClient) Skipped() []string {
+	return o.skipped
+}
+
+// FromRegistryTag restores the "+" build metadata separator that
+// registries do not allow in tags and that tools write as "_"
+func FromRegistryTag(tag string) string {
+	return strings.ReplaceAll(tag, "_", "+")
+}
+
+// reference splits the configured repository into the registry URL and
+// the repository name, e.g. registry.example.com/team/app.
+// When a base URL is configured the whole repository is the name.
+func (o *OciClient) reference() (registry string, name string, err error) {
+	ref := strings.Trim(o.config.Repository, "/")
+	if owner := strings.Trim(o.config.Owner, "/"); owner != "" {
+		ref = owner + "/" + ref
+	}
+	if ref == "" {
+		return "", "", errors.New("oci: repository is required, e.g. registry.example.com/team/app")
+	}
+
+	if o.config.BaseURL != "" {
+		return strings.TrimSuffix(o.config.BaseURL, "/"), ref, nil
+	}
+
+	host, name, found := strings.Cut(ref, "/")
+	if !found || !strings.ContainsAny(host, ".:") && host != "localhost" {
+		host, name = dockerHub, ref
+	}
+	if host == dockerHub {
+		host = dockerHubRegistry
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+
+	return "https://" + host, name, nil
+}
+
+func (o *OciClient) listTags(registry, name string) ([]string, error) {
+	registryURL, err := url.Parse(registry)
+	if err != nil {
+		return nil, fmt.Errorf("oci: invalid registry %q: %w", registry, err)
+	}
+	o.registry = registryURL
+
+	tags := []string{}
+	nextURL := fmt.Sprintf("%s/v2/%s/tags/list?n=%d", registry, name, tagsPerPage)
+
+	for nextURL != "" {
+		resp, err := o.get(nextURL)
+		if err != nil {
+			return nil, datasourceUtils.WrapError("ListTags error", err)
+		}
+
+		var list tagList
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("ListTags error: %w", err)
+		}
+
+		tags = append(tags, list.Tags...)
+		nextURL = datasourceUtils.ResolveNextLink(resp.Header, o.registry)
+	}
+
+	return tags, nil
+}
+
+// get sends an authorized GET request, answering the registry
+// authentication challenge once when the request is unauthorized
+func (o *OciClient) get(rawURL string) (*http.Response, error) {
+	resp, err := o.do(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusUnauthorized && o.authorization == "" {
+		resp.Body.Close()
+		if err := o.authenticate(resp.Header.Get("WWW-Authenticate")); err != nil {
+			return nil, err
+		}
+		resp, err = o.do(rawURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", resp.Request.URL.Redacted(), resp.Status)
+	}
+	return resp, nil
+}
+
+func (o *OciClient) do(rawURL string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the credentials are only sent to the registry, not to the other
+	// hosts its pagination links may point at
+	if o.authorization != "" && o.registry != nil && req.URL.Host == o.registry.Host {
+		req.Header.Set("Authorization", o.authorization)
+	}
+	return o.httpClient.Do(req)
+}
+
+// authenticate resolves a WWW-Authenticate challenge into the
+// Authorization header used for every following request
+func (o *OciClient) authenticate(challenge string) error {
+	scheme, params := parseChallenge(challenge)
+
+	switch scheme {
+	case "basic":
+		if o.config.Token == "" {
+			return errors.New("oci: the registry requires credentials, use --token")
+		}
+		o.authorization = "Basic " + basicCredentials(o.username(), o.config.Token)
+		return nil
+	case "bearer":
+		token, err := o.requestToken(params)
+		if err != nil {
+			return err
+		}
+		o.authorization = "Bearer " + token
+		return nil
+	default:
+		return fmt.Errorf("oci: unsupported authentication challenge %q", challenge)
+	}
+}
+
+// requestToken exchanges the optional credentials for a bearer token
+// at the realm of the challenge, anonymously when no token is set. The
+// token response is marked no-store to keep it out of the tag cache.
+func (o *OciClient) requestToken(params map[string]string) (string, error) {
+	realm, err := url.Parse(params["realm"])
+	if err != nil || params["realm"] == "" {
+		return "", fmt.Errorf("oci: invalid token realm %q", params["realm"])
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if params[key] != "" {
+			query.Set(key, params[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequest(http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Cache-Control", "no-store")
+	if o.config.Token != "" {
+		req.SetBasicAuth(o.username(), o.config.Token)
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return "", datasourceUtils.WrapError("oci: token request error", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("oci: token request error: GET %s: %s", realm.Redacted(), resp.Status)
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("oci: token request error: %w", err)
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", errors.New("oci: token request error: empty token")
 }
 
-func (g *OciCLient) FetchTags() ([]models.Version, error) {
-	return []models.Version{}, nil
+func (o *OciClient) username() string {
+	if o.config.Username != "" {
+		return o.config.Username
+	}
+	return defaultUsername
 }
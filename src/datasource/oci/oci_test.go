@@ -1 +1,237 @@
 package oci
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"src/cmd/smgr/utils"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type registryAuth string
+
+const (
+	anonymous registryAuth = "anonymous"
+	bearer    registryAuth = "bearer"
+	basic     registryAuth = "basic"
+)
+
+// newTestRegistry serves the tags of team/app in pages of two tags,
+// protected by the given kind of authentication
+func newTestRegistry(t *testing.T, auth registryAuth, tags []string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	var server *httptest.Server
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("service") != "registry.test" || r.URL.Query().Get("scope") != "repository:team/app:pull" || r.Header.Get("Cache-Control") != "no-store" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		token := "anonymous-token"
+		if user, password, ok := r.BasicAuth(); ok {
+			if password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			token = "token-for-" + user
+		}
+		json.NewEncoder(w).Encode(map[string]string{"token": token})
+	})
+
+	mux.HandleFunc("/v2/team/app/tags/list", func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		switch auth {
+		case bearer:
+			if !strings.HasPrefix(authorization, "Bearer ") {
+				w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="registry.test",scope="repository:team/app:pull"`, server.URL))
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		case basic:
+			if user, password, ok := r.BasicAuth(); !ok || user != "smgr" || password != "secret" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="registry.test"`)
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+		}
+
+		start := 0
+		if last := r.URL.Query().Get("last"); last != "" {
+			for i, tag := range tags {
+				if tag == last {
+					start = i + 1
+				}
+			}
+		}
+		end := min(start+2, len(tags))
+		if end < len(tags) {
+			w.Header().Set("Link", fmt.Sprintf(`</v2/team/app/tags/list?n=2&last=%s>; rel="next"`, tags[end-1]))
+		}
+		json.NewEncoder(w).Encode(tagList{Name: "team/app", Tags: tags[start:end]})
+	})
+
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOciClient_FetchTags(t *testing.T) {
+	tags := []string{"1.0.0", "1.1.0_build.7", "latest", "2.0.0-rc.1", "sha256-abc.sig"}
+	want := []string{"1.0.0", "1.1.0+build.7", "2.0.0-rc.1"}
+	wantSkipped := []string{"latest", "sha256-abc.sig"}
+
+	tests := []struct {
+		name     string
+		auth     registryAuth
+		token    string
+		wantAuth string
+	}{
+		{name: "Anonymous registry", auth: anonymous},
+		{name: "Anonymous bearer token", auth: bearer, wantAuth: "Bearer anonymous-token"},
+		{name: "Bearer token with credentials", auth: bearer, token: "secret", wantAuth: "Bearer token-for-smgr"},
+		{name: "Basic credentials", auth: basic, token: "secret", wantAuth: "Basic " + basicCredentials("smgr", "secret")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestRegistry(t, tt.auth, tags)
+			fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "team/app", Token: tt.token})
+
+			versions, err := fetcher.FetchTags()
+			require.NoError(t, err)
+
+			got := []string{}
+			for _, version := range versions {
+				got = append(got, version.String())
+			}
+			assert.Equal(t, want, got)
+			assert.Equal(t, wantSkipped, fetcher.Skipped())
+			assert.Equal(t, tt.wantAuth, fetcher.authorization)
+		})
+	}
+}
+
+func TestOciClient_FetchTagsLinkToAnotherHost(t *testing.T) {
+	otherAuthorization := "unset"
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		otherAuthorization = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode(tagList{Name: "team/app", Tags: []string{"2.0.0"}})
+	}))
+	t.Cleanup(other.Close)
+
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, password, ok := r.BasicAuth(); !ok || user != "smgr" || password != "secret" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="registry.test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<%s/v2/team/app/tags/list?n=2&last=1.0.0>; rel="next"`, other.URL))
+		json.NewEncoder(w).Encode(tagList{Name: "team/app", Tags: []string{"1.0.0"}})
+	}))
+	t.Cleanup(registry.Close)
+
+	versions, err := NewFetcher(&utils.DatasourceConfig{BaseURL: registry.URL, Repository: "team/app", Token: "secret"}).FetchTags()
+	require.NoError(t, err)
+	assert.Len(t, versions, 2)
+	assert.Equal(t, "", otherAuthorization)
+}
+
+func TestOciClient_FetchTagsErrors(t *testing.T) {
+	t.Run("Basic challenge without token", func(t *testing.T) {
+		server := newTestRegistry(t, basic, []string{"1.0.0"})
+		_, err := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "team/app"}).FetchTags()
+		assert.ErrorContains(t, err, "requires credentials")
+	})
+
+	t.Run("Wrong credentials", func(t *testing.T) {
+		server := newTestRegistry(t, bearer, []string{"1.0.0"})
+		_, err := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "team/app", Token: "wrong"}).FetchTags()
+		assert.ErrorContains(t, err, "401 Unauthorized")
+	})
+
+	t.Run("Unknown repository", func(t *testing.T) {
+		server := newTestRegistry(t, anonymous, []string{"1.0.0"})
+		_, err := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "team/unknown"}).FetchTags()
+		assert.ErrorContains(t, err, "404 Not Found")
+	})
+
+	t.Run("Missing repository", func(t *testing.T) {
+		_, err := NewFetcher(&utils.DatasourceConfig{}).FetchTags()
+		assert.Error(t, err)
+	})
+}
+
+func TestOciClient_reference(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       utils.DatasourceConfig
+		wantRegistry string
+		wantName     string
+	}{
+		{
+			name:         "Registry host and repository",
+			config:       utils.DatasourceConfig{Repository: "registry.example.com/team/app"},
+			wantRegistry: "https://registry.example.com",
+			wantName:     "team/app",
+		},
+		{
+			name:         "GitHub container registry with owner",
+			config:       utils.DatasourceConfig{Owner: "ghcr.io/bluepr-nt", Repository: "smgr"},
+			wantRegistry: "https://ghcr.io",
+			wantName:     "bluepr-nt/smgr",
+		},
+		{
+			name:         "Registry with port",
+			config:       utils.DatasourceConfig{Repository: "localhost:5000/app"},
+			wantRegistry: "https://localhost:5000",
+			wantName:     "app",
+		},
+		{
+			name:         "Docker Hub official image",
+			config:       utils.DatasourceConfig{Repository: "nginx"},
+			wantRegistry: "https://registry-1.docker.io",
+			wantName:     "library/nginx",
+		},
+		{
+			name:         "Docker Hub user image",
+			config:       utils.DatasourceConfig{Repository: "bitnami/nginx"},
+			wantRegistry: "https://registry-1.docker.io",
+			wantName:     "bitnami/nginx",
+		},
+		{
+			name:         "Base URL",
+			config:       utils.DatasourceConfig{BaseURL: "http://127.0.0.1:5000/", Repository: "team/app"},
+			wantRegistry: "http://127.0.0.1:5000",
+			wantName:     "team/app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry, name, err := NewFetcher(&tt.config).reference()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRegistry, registry)
+			assert.Equal(t, tt.wantName, name)
+		})
+	}
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://ghcr.io/token",service="ghcr.io",scope="repository:org/app:pull,push"`)
+	assert.Equal(t, "bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://ghcr.io/token",
+		"service": "ghcr.io",
+		"scope":   "repository:org/app:pull,push",
+	}, params)
+
+	scheme, params = parseChallenge(`Basic realm="Registry Realm"`)
+	assert.Equal(t, "basic", scheme)
+	assert.Equal(t, map[string]string{"realm": "Registry Realm"}, params)
+}
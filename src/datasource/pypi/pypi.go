@@ -0,0 +1,177 @@
+package pypi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	datasourceUtils "src/cmd/smgr/datasource/utils"
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+
+	"k8s.io/klog"
+)
+
+const (
+	defaultIndex    = "https://pypi.org"
+	defaultUsername = "__token__"
+)
+
+// pep440Regex matches the PEP 440 version scheme, groups are the epoch,
+// release, pre-release label and number, post-release, dev-release and local label
+var pep440Regex = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|alpha|b|beta|c|rc|pre|preview)[-_.]?(\d+)?)?` +
+	`((?:-\d+)|(?:[-_.]?(?:post|rev|r)[-_.]?\d*))?` +
+	`([-_.]?dev[-_.]?\d*)?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+var preReleaseLabels = map[string]string{
+	"a": "alpha", "alpha": "alpha",
+	"b": "beta", "beta": "beta",
+	"c": "rc", "rc": "rc", "pre": "rc", "preview": "rc",
+}
+
+type PypiClient struct {
+	config     *utils.DatasourceConfig
+	httpClient *http.Client
+	skipped    []string
+}
+
+type project struct {
+	Releases map[string]json.RawMessage `json:"releases"`
+}
+
+func NewFetcher(config *utils.DatasourceConfig) *PypiClient {
+	return &PypiClient{
+		config:     config,
+		httpClient: datasourceUtils.NewHTTPClient(config),
+	}
+}
+
+// FetchTags reads the releases of the configured project from the PyPI JSON
+// API and normalises them into Semver versions, see Normalize
+func (p *PypiClient) FetchTags() ([]models.Version, error) {
+	name := strings.TrimSpace(p.config.Repository)
+	if name == "" {
+		return nil, errors.New("pypi: project name is required")
+	}
+
+	doc, err := p.getProject(name)
+	if err != nil {
+		return nil, err
+	}
+
+	rawVersions := make([]string, 0, len(doc.Releases))
+	for rawVersion := range doc.Releases {
+		rawVersions = append(rawVersions, rawVersion)
+	}
+	sort.Strings(rawVersions)
+
+	versions, skipped := datasourceUtils.ParseNormalizedTags(rawVersions, Normalize)
+	p.skipped = skipped
+	if len(skipped) > 0 {
+		klog.V(1).Infof("Skipped %d non-semver versions: %s", len(skipped), strings.Join(skipped, " "))
+	}
+
+	return versions, nil
+}
+
+// Skipped returns the versions of the last fetch that did not map to Semver
+func (p *PypiClient) Skipped() []string {
+	return p.skipped
+}
+
+// Warnings lists the versions of the last fetch that did not map to Semver
+func (p *PypiClient) Warnings() []string {
+	return datasourceUtils.UnmappedWarnings("pypi", p.skipped)
+}
+
+// Normalize maps a PEP 440 version to a Semver version when the precedence
+// can be kept: the release is padded to three digits, a/b/rc pre-releases
+// become alpha/beta/rc identifiers and the local label becomes build metadata.
+// Epochs, post-releases, dev-releases and releases of more than three digits
+// do not map, an empty string is returned for them.
+func Normalize(version string) string {
+	matches := pep440Regex.FindStringSubmatch(strings.ToLower(strings.TrimSpace(version)))
+	if matches == nil {
+		return ""
+	}
+	epoch, release, preLabel, preNumber, post, dev, local := matches[1], matches[2], matches[3], matches[4], matches[5], matches[6], matches[7]
+	if epoch != "" && epoch != "0" || post != "" || dev != "" {
+		return ""
+	}
+
+	digits := strings.Split(release, ".")
+	if len(digits) > 3 {
+		return ""
+	}
+	for len(digits) < 3 {
+		digits = append(digits, "0")
+	}
+	for i, digit := range digits {
+		value, err := strconv.ParseUint(digit, 10, 64)
+		if err != nil {
+			return ""
+		}
+		digits[i] = strconv.FormatUint(value, 10)
+	}
+	normalized := strings.Join(digits, ".")
+
+	if preLabel != "" {
+		if preNumber == "" {
+			preNumber = "0"
+		}
+		value, err := strconv.ParseUint(preNumber, 10, 64)
+		if err != nil {
+			return ""
+		}
+		normalized = fmt.Sprintf("%s-%s.%d", normalized, preReleaseLabels[preLabel], value)
+	}
+
+	if local != "" {
+		normalized += "+" + strings.NewReplacer("-", ".", "_", ".").Replace(local)
+	}
+	return normalized
+}
+
+func (p *PypiClient) baseURL() string {
+	if p.config.BaseURL == "" {
+		return defaultIndex
+	}
+	return strings.TrimSuffix(p.config.BaseURL, "/")
+}
+
+func (p *PypiClient) getProject(name string) (*project, error) {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/pypi/%s/json", p.baseURL(), name), nil)
+	if err != nil {
+		return nil, fmt.Errorf("pypi: project error: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.config.Token != "" {
+		username := p.config.Username
+		if username == "" {
+			username = defaultUsername
+		}
+		req.SetBasicAuth(username, p.config.Token)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, datasourceUtils.WrapError("pypi: project error", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("pypi: project error: GET %s: %s", req.URL.Redacted(), resp.Status)
+	}
+
+	doc := &project{}
+	if err := json.NewDecoder(resp.Body).Decode(doc); err != nil {
+		return nil, fmt.Errorf("pypi: project error: %w", err)
+	}
+	return doc, nil
+}
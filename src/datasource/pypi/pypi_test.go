@@ -0,0 +1,92 @@
+package pypi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"src/cmd/smgr/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"1.2.3", "1.2.3"},
+		{"1.2", "1.2.0"},
+		{"2", "2.0.0"},
+		{"v1.02.3", "1.2.3"},
+		{"1.0a1", "1.0.0-alpha.1"},
+		{"1.0.0b2", "1.0.0-beta.2"},
+		{"1.0.0.rc.1", "1.0.0-rc.1"},
+		{"1.0c3", "1.0.0-rc.3"},
+		{"1.0-preview", "1.0.0-rc.0"},
+		{"1.0.0+ubuntu-1_2", "1.0.0+ubuntu.1.2"},
+		{"0!1.0.0", "1.0.0"},
+		{"1!1.0.0", ""},
+		{"1.0.0.post1", ""},
+		{"1.0.0.dev3", ""},
+		{"1.2.3.4", ""},
+		{"latest", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Normalize(tt.version))
+		})
+	}
+}
+
+func TestPypiClient_FetchTags(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pypi/requests/json":
+			fmt.Fprint(w, `{"info":{"name":"requests"},"releases":{"2.31.0":[],"2.32.0rc1":[],"2.0":[],"1.0.0.post1":[]}}`)
+		case "/pypi/private/json":
+			if username, password, _ := r.BasicAuth(); username != "__token__" || password != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"releases":{"0.1.0":[]}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("Public project", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "requests"})
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+
+		got := []string{}
+		for _, version := range versions {
+			got = append(got, version.String())
+		}
+		assert.Equal(t, []string{"2.0.0", "2.31.0", "2.32.0-rc.1"}, got)
+		assert.Equal(t, []string{"1.0.0.post1"}, fetcher.Skipped())
+		assert.Equal(t, []string{"pypi: 1 versions do not map to Semver: 1.0.0.post1"}, fetcher.Warnings())
+	})
+
+	t.Run("Private project with token", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "private", Token: "secret"})
+		versions, err := fetcher.FetchTags()
+		require.NoError(t, err)
+		assert.Len(t, versions, 1)
+	})
+
+	t.Run("Unknown project", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL, Repository: "unknown"})
+		_, err := fetcher.FetchTags()
+		assert.ErrorContains(t, err, "404 Not Found")
+	})
+
+	t.Run("Missing project", func(t *testing.T) {
+		fetcher := NewFetcher(&utils.DatasourceConfig{BaseURL: server.URL})
+		_, err := fetcher.FetchTags()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	sharedUtils "src/cmd/smgr/utils"
+)
+
+// NewHTTPClient returns the client of an HTTP datasource, retrying its
+// requests through the configured transport, or http.DefaultTransport
+func NewHTTPClient(config *sharedUtils.DatasourceConfig) *http.Client {
+	return &http.Client{Transport: NewRetryTransport(config.Transport)}
+}
+
+// NewCATransport returns a transport trusting the certificates of the PEM
+// bundle at path on top of the system ones, e.g. for the private CA of a
+// self-hosted instance
+func NewCATransport(path string) (*http.Transport, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("CA bundle error: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.New("CA bundle error: no PEM certificate found in " + path)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	return transport, nil
+}
+
+// NextLink returns the absolute URL of the rel="next" entry of the
+// response Link header, or an empty string when there is no next page.
+// Relative links are resolved against the request URL.
+func NextLink(resp *http.Response) string {
+	var base *url.URL
+	if resp.Request != nil {
+		base = resp.Request.URL
+	}
+	return ResolveNextLink(resp.Header, base)
+}
+
+// ResolveNextLink returns the absolute URL of the rel="next" entry of the
+// Link header, relative links are resolved against base when it is set
+func ResolveNextLink(header http.Header, base *url.URL) string {
+	for _, link := range strings.Split(header.Get("Link"), ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+
+		isNext := false
+		for _, param := range parts[1:] {
+			param = strings.ReplaceAll(strings.TrimSpace(param), " ", "")
+			if param == `rel="next"` || param == "rel=next" {
+				isNext = true
+			}
+		}
+		if !isNext {
+			continue
+		}
+
+		rawURL := strings.Trim(strings.TrimSpace(parts[0]), "<>")
+		if base == nil {
+			return rawURL
+		}
+		next, err := base.Parse(rawURL)
+		if err != nil {
+			return ""
+		}
+		return next.String()
+	}
+	return ""
+}
@@ -0,0 +1,97 @@
+package utils
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextLink(t *testing.T) {
+	requestURL, _ := url.Parse("https://registry.example.com/v2/team/app/tags/list")
+	tests := []struct {
+		name string
+		link string
+		want string
+	}{
+		{
+			name: "No Link header",
+			link: "",
+			want: "",
+		},
+		{
+			name: "Absolute next link",
+			link: `<https://gitlab.com/api/v4/projects/1/repository/tags?page_token=abc>; rel="next"`,
+			want: "https://gitlab.com/api/v4/projects/1/repository/tags?page_token=abc",
+		},
+		{
+			name: "Relative next link",
+			link: `</v2/team/app/tags/list?last=1.0.0&n=100>; rel="next"`,
+			want: "https://registry.example.com/v2/team/app/tags/list?last=1.0.0&n=100",
+		},
+		{
+			name: "Next link among other relations",
+			link: `<https://api.example.com/tags?page=1>; rel="first", <https://api.example.com/tags?page=3>; rel="next"`,
+			want: "https://api.example.com/tags?page=3",
+		},
+		{
+			name: "Only last link",
+			link: `<https://api.example.com/tags?page=9>; rel="last"`,
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				Header:  http.Header{"Link": []string{tt.link}},
+				Request: &http.Request{URL: requestURL},
+			}
+			assert.Equal(t, tt.want, NextLink(resp))
+		})
+	}
+}
+
+func TestResolveNextLink(t *testing.T) {
+	registryURL, _ := url.Parse("https://registry.example.com")
+	header := http.Header{"Link": []string{`</v2/team/app/tags/list?last=1.0.0>; rel="next"`}}
+
+	assert.Equal(t, "https://registry.example.com/v2/team/app/tags/list?last=1.0.0", ResolveNextLink(header, registryURL))
+	assert.Equal(t, "/v2/team/app/tags/list?last=1.0.0", ResolveNextLink(header, nil))
+}
+
+func TestNewCATransport(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	t.Run("PEM bundle", func(t *testing.T) {
+		path := filepath.Join(dir, "ca.pem")
+		bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+		require.NoError(t, os.WriteFile(path, bundle, 0o600))
+
+		transport, err := NewCATransport(path)
+		require.NoError(t, err)
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	})
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := NewCATransport(filepath.Join(dir, "missing.pem"))
+		assert.ErrorContains(t, err, "CA bundle error")
+	})
+
+	t.Run("No certificate", func(t *testing.T) {
+		path := filepath.Join(dir, "empty.pem")
+		require.NoError(t, os.WriteFile(path, []byte("not a certificate"), 0o600))
+		_, err := NewCATransport(path)
+		assert.ErrorContains(t, err, "no PEM certificate found")
+	})
+}
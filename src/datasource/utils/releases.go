@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"strings"
+	"time"
+
+	"src/cmd/smgr/models"
+)
+
+const (
+	// TagsKind reads the versions of a platform from its tags
+	TagsKind = "tags"
+	// ReleasesKind reads the versions of a platform from its releases
+	ReleasesKind = "releases"
+)
+
+// Release is a version published as a platform release with its flags
+type Release struct {
+	Version    models.Version
+	Draft      bool
+	Prerelease bool
+	Latest     bool
+	// PublishedAt is zero for the drafts
+	PublishedAt time.Time
+}
+
+// Annotation describes the flags and the publish date of the release,
+// e.g. "latest, published 2024-05-01"
+func (r Release) Annotation() string {
+	annotations := []string{}
+	if r.Draft {
+		annotations = append(annotations, "draft")
+	}
+	if r.Prerelease {
+		annotations = append(annotations, "prerelease")
+	}
+	if r.Latest {
+		annotations = append(annotations, "latest")
+	}
+	if !r.PublishedAt.IsZero() {
+		annotations = append(annotations, "published "+r.PublishedAt.UTC().Format(time.DateOnly))
+	}
+	return strings.Join(annotations, ", ")
+}
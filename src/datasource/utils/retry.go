@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"k8s.io/klog"
+)
+
+const (
+	defaultMaxRetries = 4
+	defaultBaseDelay  = time.Second
+	defaultMaxDelay   = time.Minute
+)
+
+// RetryBudgetError is returned when a request still fails once every retry
+// was spent, or when the server asks to wait longer than the transport may
+type RetryBudgetError struct {
+	Method   string
+	URL      string
+	Attempts int
+	// Status is the status of the last response, empty after a network error
+	Status string
+	// RetryAfter is the wait the server asked for when it was too long
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *RetryBudgetError) Error() string {
+	reason := e.Status
+	if e.Err != nil {
+		reason = e.Err.Error()
+	}
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s %s: rate limited, retry in %s: %s", e.Method, e.URL, e.RetryAfter.Round(time.Second), reason)
+	}
+	return fmt.Sprintf("%s %s: retry budget exhausted after %d attempts: %s", e.Method, e.URL, e.Attempts, reason)
+}
+
+func (e *RetryBudgetError) Unwrap() error {
+	return e.Err
+}
+
+// WrapError prefixes err with the operation that failed, an exhausted retry
+// budget is returned as is so that it is reported clearly
+func WrapError(operation string, err error) error {
+	var budgetErr *RetryBudgetError
+	if errors.As(err, &budgetErr) {
+		return budgetErr
+	}
+	return fmt.Errorf("%s: %w", operation, err)
+}
+
+// RetryTransport retries the GET and HEAD requests of the datasources on
+// network errors, rate limits and server errors. It waits as long as the
+// Retry-After or X-RateLimit-Reset headers ask, or backs off exponentially
+// with full jitter when there are none.
+type RetryTransport struct {
+	Next       http.RoundTripper
+	MaxRetries int
+	BaseDelay  time.Duration
+	// MaxDelay is the longest wait between two attempts, a server asking
+	// for a longer wait fails the request at once
+	MaxDelay time.Duration
+	now      func() time.Time
+	sleep    func(ctx context.Context, delay time.Duration) error
+}
+
+func NewRetryTransport(next http.RoundTripper) *RetryTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &RetryTransport{
+		Next:       next,
+		MaxRetries: defaultMaxRetries,
+		BaseDelay:  defaultBaseDelay,
+		MaxDelay:   defaultMaxDelay,
+		now:        time.Now,
+		sleep:      sleep,
+	}
+}
+
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead || req.Body != nil && req.Body != http.NoBody {
+		return t.Next.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		resp, err := t.Next.RoundTrip(req)
+		if err == nil && !retryable(resp) {
+			return resp, nil
+		}
+		if err != nil && (req.Context().Err() != nil || !retryableError(err)) {
+			return nil, err
+		}
+
+		budgetErr := &RetryBudgetError{
+			Method:   req.Method,
+			URL:      req.URL.Redacted(),
+			Attempts: attempt + 1,
+			Err:      err,
+		}
+		delay, requested := t.backoff(attempt), time.Duration(0)
+		if resp != nil {
+			budgetErr.Status = resp.Status
+			if wait, found := t.requestedDelay(resp); found {
+				delay, requested = wait, wait
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if requested > t.MaxDelay {
+			budgetErr.RetryAfter = requested
+			return nil, budgetErr
+		}
+		if attempt >= t.MaxRetries {
+			return nil, budgetErr
+		}
+
+		klog.V(1).Infof("Retrying %s %s in %s after attempt %d failed", req.Method, req.URL.Redacted(), delay, attempt+1)
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryable returns true for the responses of a rate limit or a
+// temporary server error
+func retryable(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	case http.StatusForbidden:
+		// GitHub answers 403 to the requests over a primary or secondary rate limit
+		return resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != ""
+	}
+	return false
+}
+
+// retryableError returns false for the network errors a retry cannot fix,
+// such as an untrusted certificate
+func retryableError(err error) bool {
+	var verificationErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return !errors.As(err, &verificationErr) && !errors.As(err, &unknownAuthorityErr) &&
+		!errors.As(err, &hostnameErr) && !errors.As(err, &invalidErr)
+}
+
+// backoff returns a random delay up to BaseDelay * 2^attempt, capped by MaxDelay
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	ceiling := t.MaxDelay
+	if attempt < 32 && t.BaseDelay<<attempt > 0 && t.BaseDelay<<attempt < ceiling {
+		ceiling = t.BaseDelay << attempt
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// requestedDelay reads the wait asked by the Retry-After header, in seconds
+// or as an HTTP date, or by the X-RateLimit-Reset epoch of an exhausted
+// rate limit
+func (t *RetryTransport) requestedDelay(resp *http.Response) (time.Duration, bool) {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.ParseInt(retryAfter, 10, 64); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return nonNegative(date.Sub(t.now())), true
+		}
+	}
+
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return nonNegative(time.Unix(reset, 0).Sub(t.now())), true
+		}
+	}
+	return 0, false
+}
+
+func nonNegative(delay time.Duration) time.Duration {
+	if delay < 0 {
+		return 0
+	}
+	return delay
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
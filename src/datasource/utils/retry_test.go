@@ -0,0 +1,174 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTransport returns a RetryTransport recording its waits instead
+// of sleeping
+func newTestTransport(now time.Time) (*RetryTransport, *[]time.Duration) {
+	delays := []time.Duration{}
+	transport := NewRetryTransport(nil)
+	transport.now = func() time.Time { return now }
+	transport.sleep = func(ctx context.Context, delay time.Duration) error {
+		delays = append(delays, delay)
+		return nil
+	}
+	return transport, &delays
+}
+
+// newFlakyServer answers with the responses in order, then with 200 OK
+func newFlakyServer(t *testing.T, responses ...func(w http.ResponseWriter)) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= len(responses) {
+			responses[requests-1](w)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func status(code int, headers ...string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(code)
+	}
+}
+
+func TestRetryTransport(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Server errors are retried with backoff", func(t *testing.T) {
+		server, requests := newFlakyServer(t, status(http.StatusBadGateway), status(http.StatusServiceUnavailable))
+		transport, delays := newTestTransport(now)
+
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, 3, *requests)
+		require.Len(t, *delays, 2)
+		assert.LessOrEqual(t, (*delays)[0], time.Second)
+		assert.LessOrEqual(t, (*delays)[1], 2*time.Second)
+	})
+
+	t.Run("Retry-After in seconds", func(t *testing.T) {
+		server, _ := newFlakyServer(t, status(http.StatusTooManyRequests, "Retry-After", "7"))
+		transport, delays := newTestTransport(now)
+
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, []time.Duration{7 * time.Second}, *delays)
+	})
+
+	t.Run("Retry-After as a date", func(t *testing.T) {
+		server, _ := newFlakyServer(t, status(http.StatusServiceUnavailable, "Retry-After", now.Add(30*time.Second).Format(http.TimeFormat)))
+		transport, delays := newTestTransport(now)
+
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, []time.Duration{30 * time.Second}, *delays)
+	})
+
+	t.Run("Exhausted GitHub rate limit", func(t *testing.T) {
+		reset := strconv.FormatInt(now.Add(20*time.Second).Unix(), 10)
+		server, _ := newFlakyServer(t, status(http.StatusForbidden, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset))
+		transport, delays := newTestTransport(now)
+
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, []time.Duration{20 * time.Second}, *delays)
+	})
+
+	t.Run("Client errors are not retried", func(t *testing.T) {
+		server, requests := newFlakyServer(t, status(http.StatusForbidden), status(http.StatusNotFound))
+		transport, delays := newTestTransport(now)
+
+		resp, err := (&http.Client{Transport: transport}).Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+		assert.Equal(t, 1, *requests)
+		assert.Empty(t, *delays)
+	})
+
+	t.Run("Exhausted budget", func(t *testing.T) {
+		server, requests := newFlakyServer(t,
+			status(http.StatusBadGateway), status(http.StatusBadGateway), status(http.StatusBadGateway),
+			status(http.StatusBadGateway), status(http.StatusBadGateway), status(http.StatusBadGateway))
+		transport, _ := newTestTransport(now)
+
+		_, err := (&http.Client{Transport: transport}).Get(server.URL)
+		var budgetErr *RetryBudgetError
+		require.ErrorAs(t, err, &budgetErr)
+		assert.Equal(t, 5, budgetErr.Attempts)
+		assert.Equal(t, 5, *requests)
+		assert.ErrorContains(t, WrapError("ListTags error", err), "retry budget exhausted after 5 attempts: 502 Bad Gateway")
+		assert.NotContains(t, WrapError("ListTags error", err).Error(), "ListTags error")
+	})
+
+	t.Run("Rate limit resetting after the longest wait", func(t *testing.T) {
+		reset := strconv.FormatInt(now.Add(time.Hour).Unix(), 10)
+		server, requests := newFlakyServer(t, status(http.StatusForbidden, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", reset))
+		transport, delays := newTestTransport(now)
+
+		_, err := (&http.Client{Transport: transport}).Get(server.URL)
+		var budgetErr *RetryBudgetError
+		require.ErrorAs(t, err, &budgetErr)
+		assert.Equal(t, time.Hour, budgetErr.RetryAfter)
+		assert.ErrorContains(t, err, "rate limited, retry in 1h0m0s: 403 Forbidden")
+		assert.Equal(t, 1, *requests)
+		assert.Empty(t, *delays)
+	})
+
+	t.Run("Network errors", func(t *testing.T) {
+		server, _ := newFlakyServer(t)
+		server.Close()
+		transport, delays := newTestTransport(now)
+
+		_, err := (&http.Client{Transport: transport}).Get(server.URL)
+		var budgetErr *RetryBudgetError
+		require.ErrorAs(t, err, &budgetErr)
+		assert.Len(t, *delays, 4)
+		assert.Empty(t, budgetErr.Status)
+	})
+
+	t.Run("Other methods are not retried", func(t *testing.T) {
+		server, requests := newFlakyServer(t, status(http.StatusBadGateway))
+		transport, _ := newTestTransport(now)
+
+		resp, err := (&http.Client{Transport: transport}).Post(server.URL, "text/plain", nil)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+		assert.Equal(t, 1, *requests)
+	})
+}
+
+func TestWrapError(t *testing.T) {
+	err := errors.New("boom")
+	assert.EqualError(t, WrapError("ListTags error", err), "ListTags error: boom")
+
+	budgetErr := &RetryBudgetError{Method: http.MethodGet, URL: "https://example.com", Attempts: 2, Status: "503 Service Unavailable"}
+	assert.Same(t, budgetErr, WrapError("ListTags error", fmt.Errorf("wrapped: %w", budgetErr)))
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+
+	"src/cmd/smgr/models"
+
+	"k8s.io/klog"
+)
+
+// ParseTags parses raw tag names into Versions using the models parser.
+// Tags that are not Semver compliant are returned separately as skipped.
+func ParseTags(tags []string) (versions []models.Version, skipped []string) {
+	return ParseNormalizedTags(tags, nil)
+}
+
+// ParseNormalizedTags is like ParseTags but rewrites every tag with normalize
+// before parsing it, skipped tags are returned as they were received.
+func ParseNormalizedTags(tags []string, normalize func(string) string) (versions []models.Version, skipped []string) {
+	for _, tag := range tags {
+		rawVersion := tag
+		if normalize != nil {
+			rawVersion = normalize(tag)
+		}
+
+		version, err := models.ParseVersion(rawVersion)
+		if err != nil {
+			skipped = append(skipped, tag)
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return versions, skipped
+}
+
+// UnmappedWarnings returns a warning listing the ecosystem versions
+// that could not be normalised into Semver versions
+func UnmappedWarnings(platform string, unmapped []string) []string {
+	if len(unmapped) == 0 {
+		return []string{}
+	}
+	return []string{fmt.Sprintf("%s: %d versions do not map to Semver: %s", platform, len(unmapped), strings.Join(unmapped, " "))}
+}
+
+// NamespaceTags returns the versions of the tags of a namespace, stripped of
+// the namespace template. The tags of other namespaces are dropped, all the
+// tags are returned unchanged for the empty namespace.
+func NamespaceTags(namespace models.Namespace, tags []string) []string {
+	if namespace.IsEmpty() {
+		return tags
+	}
+
+	versions := []string{}
+	for _, tag := range tags {
+		if version, found := namespace.Strip(tag); found {
+			versions = append(versions, version)
+		}
+	}
+	klog.V(1).Infof("Kept %d of %d tags in namespace %s", len(versions), len(tags), namespace)
+	return versions
+}
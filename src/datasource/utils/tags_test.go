@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"src/cmd/smgr/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNormalizedTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		tags        []string
+		normalize   func(string) string
+		want        []string
+		wantSkipped []string
+	}{
+		{
+			name:        "Without normalization",
+			tags:        []string{"1.0.0", "v1.1.0", "latest", "2.0.0+build.1"},
+			want:        []string{"1.0.0", "v1.1.0", "2.0.0+build.1"},
+			wantSkipped: []string{"latest"},
+		},
+		{
+			name:        "Skipped tags keep their original name",
+			tags:        []string{"1.0.0_build.1", "1.0.0_build_1"},
+			normalize:   func(tag string) string { return strings.ReplaceAll(tag, "_", "+") },
+			want:        []string{"1.0.0+build.1"},
+			wantSkipped: []string{"1.0.0_build_1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			versions, skipped := ParseNormalizedTags(tt.tags, tt.normalize)
+			got := []string{}
+			for _, version := range versions {
+				got = append(got, version.String())
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantSkipped, skipped)
+		})
+	}
+}
+
+func TestNamespaceTags(t *testing.T) {
+	tags := []string{"service-a/v1.0.0", "service-b/v2.0.0", "service-a/v1.1.0-rc.1", "1.2.0"}
+
+	namespace, err := models.ParseNamespace("service-a/v")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.0.0", "1.1.0-rc.1"}, NamespaceTags(namespace, tags))
+	assert.Equal(t, tags, NamespaceTags(models.Namespace{}, tags))
+}
@@ -0,0 +1,350 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Operator compares a version with the version of a Comparator
+type Operator string
+
+const (
+	Equal          Operator = "="
+	Greater        Operator = ">"
+	GreaterOrEqual Operator = ">="
+	Less           Operator = "<"
+	LessOrEqual    Operator = "<="
+)
+
+// operatorSpaces matches the spaces allowed between an operator and its version
+var operatorSpaces = regexp.MustCompile(`(<=|>=|<|>|=|~|\^)\s+`)
+
+// Comparator is a single version requirement such as >=1.2.0
+type Comparator struct {
+	Operator Operator
+	Version  Version
+}
+
+// Check returns true if the version satisfies the comparator
+func (c Comparator) Check(version Version) bool {
+	cmp := compareVersions(version, c.Version)
+	switch c.Operator {
+	case Greater:
+		return cmp > 0
+	case GreaterOrEqual:
+		return cmp >= 0
+	case Less:
+		return cmp < 0
+	case LessOrEqual:
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+func (c Comparator) String() string {
+	return string(c.Operator) + c.Version.String()
+}
+
+// ComparatorSet is the intersection of comparators, e.g. >=1.2.0 <2.0.0-0
+type ComparatorSet []Comparator
+
+// Check returns true if the version satisfies every comparator of the set.
+// A prerelease version only satisfies a set having a comparator on a
+// prerelease of the same release, so that >=1.2.0-rc.1 <2.0.0 matches
+// 1.2.0-rc.2 but not 1.5.0-rc.1.
+func (s ComparatorSet) Check(version Version) bool {
+	for _, comparator := range s {
+		if !comparator.Check(version) {
+			return false
+		}
+	}
+	if version.IsRelease() {
+		return true
+	}
+
+	for _, comparator := range s {
+		if !comparator.Version.IsRelease() && comparator.Version.Release.IsEqualTo(version.Release) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s ComparatorSet) String() string {
+	comparators := []string{}
+	for _, comparator := range s {
+		comparators = append(comparators, comparator.String())
+	}
+	return strings.Join(comparators, " ")
+}
+
+// Constraint is a range of versions in the npm syntax, the union of the
+// comparator sets separated by ||. A set is made of space or comma separated
+// comparators (=, >, >=, <, <=), partial versions with x or * wildcards,
+// tilde ~1.2.3 and caret ^1.2.3 ranges, or a hyphen range 1.2.3 - 2.3.4.
+// A bare version is an exact match.
+type Constraint struct {
+	Sets []ComparatorSet
+	raw  string
+}
+
+// ParseConstraint parses a constraint such as ">=1.0.0 <2.0.0 || ^3.1"
+func ParseConstraint(constraint string) (Constraint, error) {
+	if strings.TrimSpace(constraint) == "" {
+		return Constraint{}, errors.New("constraint MUST NOT be empty")
+	}
+
+	result := Constraint{raw: strings.TrimSpace(constraint)}
+	for _, rawSet := range strings.Split(constraint, "||") {
+		set, err := parseComparatorSet(rawSet)
+		if err != nil {
+			return Constraint{}, fmt.Errorf("invalid constraint %q: %w", constraint, err)
+		}
+		result.Sets = append(result.Sets, set)
+	}
+	return result, nil
+}
+
+// Check returns true if the version satisfies one of the comparator sets
+func (c Constraint) Check(version Version) bool {
+	for _, set := range c.Sets {
+		if set.Check(version) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c Constraint) String() string {
+	return c.raw
+}
+
+func parseComparatorSet(rawSet string) (ComparatorSet, error) {
+	rawSet = strings.ReplaceAll(rawSet, ",", " ")
+	rawSet = operatorSpaces.ReplaceAllString(strings.TrimSpace(rawSet), "$1")
+	tokens := strings.Fields(rawSet)
+	if len(tokens) == 0 {
+		return ComparatorSet{{Operator: GreaterOrEqual, Version: Version{}}}, nil
+	}
+
+	if len(tokens) == 3 && tokens[1] == "-" {
+		return parseHyphenRange(tokens[0], tokens[2])
+	}
+
+	set := ComparatorSet{}
+	for _, token := range tokens {
+		if token == "-" {
+			return nil, errors.New("hyphen ranges MUST be written as VERSION - VERSION")
+		}
+		comparators, err := parseComparators(token)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, comparators...)
+	}
+	return set, nil
+}
+
+func parseHyphenRange(rawFrom, rawTo string) (ComparatorSet, error) {
+	from, err := parsePartialVersion(rawFrom)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parsePartialVersion(rawTo)
+	if err != nil {
+		return nil, err
+	}
+
+	set := ComparatorSet{{Operator: GreaterOrEqual, Version: from.lower()}}
+	switch {
+	case to.isFull():
+		set = append(set, Comparator{Operator: LessOrEqual, Version: to.lower()})
+	case len(to.numbers) > 0:
+		set = append(set, Comparator{Operator: Less, Version: lowestPrerelease(to.next(len(to.numbers)))})
+	}
+	return set, nil
+}
+
+func parseComparators(token string) ([]Comparator, error) {
+	operator := ""
+	for _, candidate := range []string{">=", "<=", ">", "<", "=", "~", "^"} {
+		if strings.HasPrefix(token, candidate) {
+			operator = candidate
+			break
+		}
+	}
+
+	partial, err := parsePartialVersion(strings.TrimPrefix(token, operator))
+	if err != nil {
+		return nil, err
+	}
+	anyVersion := []Comparator{{Operator: GreaterOrEqual, Version: Version{}}}
+	noVersion := []Comparator{{Operator: Less, Version: lowestPrerelease(Version{})}}
+	given := len(partial.numbers)
+
+	switch operator {
+	case "", "=":
+		if given == 0 {
+			return anyVersion, nil
+		}
+		if partial.isFull() {
+			return []Comparator{{Operator: Equal, Version: partial.lower()}}, nil
+		}
+		return partial.between(given), nil
+	case ">":
+		if given == 0 {
+			return noVersion, nil
+		}
+		if partial.isFull() {
+			return []Comparator{{Operator: Greater, Version: partial.lower()}}, nil
+		}
+		return []Comparator{{Operator: GreaterOrEqual, Version: partial.next(given)}}, nil
+	case ">=":
+		return []Comparator{{Operator: GreaterOrEqual, Version: partial.lower()}}, nil
+	case "<":
+		if given == 0 {
+			return noVersion, nil
+		}
+		if partial.isFull() {
+			return []Comparator{{Operator: Less, Version: partial.lower()}}, nil
+		}
+		return []Comparator{{Operator: Less, Version: lowestPrerelease(partial.lower())}}, nil
+	case "<=":
+		if given == 0 {
+			return anyVersion, nil
+		}
+		if partial.isFull() {
+			return []Comparator{{Operator: LessOrEqual, Version: partial.lower()}}, nil
+		}
+		return []Comparator{{Operator: Less, Version: lowestPrerelease(partial.next(given))}}, nil
+	case "~":
+		if given == 0 {
+			return anyVersion, nil
+		}
+		return partial.between(min(given, 2)), nil
+	default:
+		if given == 0 {
+			return anyVersion, nil
+		}
+		// the first non-zero identifier may not change, or the last one given
+		significant := 0
+		for significant < given-1 && partial.numbers[significant] == 0 {
+			significant++
+		}
+		return partial.between(significant + 1), nil
+	}
+}
+
+// partialVersion is a version whose trailing identifiers may be omitted or
+// wildcards, e.g. 1.2, 1.x or *
+type partialVersion struct {
+	numbers    []uint64
+	prerelease string
+}
+
+func parsePartialVersion(raw string) (partialVersion, error) {
+	rawVersion := strings.TrimPrefix(raw, VersionPrefix)
+	rawVersion, _, _ = strings.Cut(rawVersion, "+")
+	rawVersion, prerelease, hasPrerelease := strings.Cut(rawVersion, "-")
+	if rawVersion == "" {
+		return partialVersion{}, fmt.Errorf("missing version in %q", raw)
+	}
+
+	identifiers := strings.Split(rawVersion, ".")
+	if len(identifiers) > 3 {
+		return partialVersion{}, fmt.Errorf("version %q MUST have at most three dot-separated identifiers", raw)
+	}
+
+	partial := partialVersion{}
+	for i, identifier := range identifiers {
+		if isWildcard(identifier) {
+			for _, next := range identifiers[i+1:] {
+				if !isWildcard(next) {
+					return partialVersion{}, fmt.Errorf("version %q MUST NOT have a number after a wildcard", raw)
+				}
+			}
+			break
+		}
+
+		number, err := strconv.ParseUint(identifier, 10, 64)
+		if err != nil || len(identifier) > 1 && identifier[0] == '0' {
+			return partialVersion{}, fmt.Errorf("version %q identifiers MUST be numbers without leading zeros or x, X, * wildcards, got: %s", raw, identifier)
+		}
+		partial.numbers = append(partial.numbers, number)
+	}
+
+	if hasPrerelease {
+		if !partial.isFull() {
+			return partialVersion{}, fmt.Errorf("version %q MUST be complete to have a prerelease", raw)
+		}
+		if _, err := ParsePRVersion(prerelease); err != nil {
+			return partialVersion{}, fmt.Errorf("version %q: %w", raw, err)
+		}
+		partial.prerelease = prerelease
+	}
+	return partial, nil
+}
+
+func isWildcard(identifier string) bool {
+	return identifier == "x" || identifier == "X" || identifier == Wildcard
+}
+
+func (p partialVersion) isFull() bool {
+	return len(p.numbers) == 3
+}
+
+// lower returns the lowest version of the partial version, 1.2 gives 1.2.0
+func (p partialVersion) lower() Version {
+	numbers := append(append([]uint64{}, p.numbers...), 0, 0, 0)
+	return makeVersion(numbers[0], numbers[1], numbers[2], p.prerelease)
+}
+
+// next returns the release following the first identifiers of the
+// partial version, 1.2.3 gives 2.0.0 for 1 identifier and 1.3.0 for 2
+func (p partialVersion) next(identifiers int) Version {
+	numbers := append(append([]uint64{}, p.numbers[:identifiers]...), 0, 0, 0)
+	numbers[identifiers-1]++
+	for i := identifiers; i < 3; i++ {
+		numbers[i] = 0
+	}
+	return makeVersion(numbers[0], numbers[1], numbers[2], "")
+}
+
+// between returns the comparators of the versions from the partial version
+// up to the next release of its first identifiers, excluding its prereleases
+func (p partialVersion) between(identifiers int) []Comparator {
+	return []Comparator{
+		{Operator: GreaterOrEqual, Version: p.lower()},
+		{Operator: Less, Version: lowestPrerelease(p.next(identifiers))},
+	}
+}
+
+func makeVersion(major, minor, patch uint64, prerelease string) Version {
+	rawVersion := fmt.Sprintf("%d.%d.%d", major, minor, patch)
+	if prerelease != "" {
+		rawVersion += "-" + prerelease
+	}
+	version, _ := ParseVersion(rawVersion)
+	return version
+}
+
+// lowestPrerelease returns the lowest prerelease of a release, e.g. 2.0.0-0
+func lowestPrerelease(version Version) Version {
+	return makeVersion(version.Release.Major.Value(), version.Release.Minor.Value(), version.Release.Patch.Value(), "0")
+}
+
+// compareVersions returns 1, 0 or -1 when a is higher than, equal to or
+// lower than b
+func compareVersions(a, b Version) int {
+	if a.IsHigherThan(b) {
+		return 1
+	}
+	if b.IsHigherThan(a) {
+		return -1
+	}
+	return 0
+}
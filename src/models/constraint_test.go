@@ -0,0 +1,104 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConstraint(t *testing.T) {
+	tests := []struct {
+		constraint string
+		want       string
+		wantErr    bool
+	}{
+		{constraint: "1.2.3", want: "=1.2.3"},
+		{constraint: "=v1.2.3", want: "=1.2.3"},
+		{constraint: "1.2", want: ">=1.2.0 <1.3.0-0"},
+		{constraint: "1.x", want: ">=1.0.0 <2.0.0-0"},
+		{constraint: "*", want: ">=0.0.0"},
+		{constraint: ">=1.0.0 <2.0.0", want: ">=1.0.0 <2.0.0"},
+		{constraint: ">= 1.0.0, < 2.0.0", want: ">=1.0.0 <2.0.0"},
+		{constraint: ">1.2", want: ">=1.3.0"},
+		{constraint: "<1.2", want: "<1.2.0-0"},
+		{constraint: "<=1.2", want: "<1.3.0-0"},
+		{constraint: ">*", want: "<0.0.0-0"},
+		{constraint: "~1.2.3", want: ">=1.2.3 <1.3.0-0"},
+		{constraint: "~1", want: ">=1.0.0 <2.0.0-0"},
+		{constraint: "~1.2.3-beta.2", want: ">=1.2.3-beta.2 <1.3.0-0"},
+		{constraint: "^1.2.3", want: ">=1.2.3 <2.0.0-0"},
+		{constraint: "^0.2.3", want: ">=0.2.3 <0.3.0-0"},
+		{constraint: "^0.0.3", want: ">=0.0.3 <0.0.4-0"},
+		{constraint: "^0.0", want: ">=0.0.0 <0.1.0-0"},
+		{constraint: "^0", want: ">=0.0.0 <1.0.0-0"},
+		{constraint: "^1.2", want: ">=1.2.0 <2.0.0-0"},
+		{constraint: "1.2.3 - 2.3.4", want: ">=1.2.3 <=2.3.4"},
+		{constraint: "1.2 - 2.3", want: ">=1.2.0 <2.4.0-0"},
+		{constraint: "1.2.3 - 2", want: ">=1.2.3 <3.0.0-0"},
+		{constraint: "^1.2 || ~3.1.0", want: ">=1.2.0 <2.0.0-0 || >=3.1.0 <3.2.0-0"},
+		{constraint: "", wantErr: true},
+		{constraint: "1.2.3.4", wantErr: true},
+		{constraint: "1.x.3", wantErr: true},
+		{constraint: "1.2-beta", wantErr: true},
+		{constraint: "01.2.3", wantErr: true},
+		{constraint: ">=a.b.c", wantErr: true},
+		{constraint: "1.0.0 - 2.0.0 - 3.0.0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint, func(t *testing.T) {
+			constraint, err := ParseConstraint(tt.constraint)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			sets := []string{}
+			for _, set := range constraint.Sets {
+				sets = append(sets, set.String())
+			}
+			assert.Equal(t, tt.want, strings.Join(sets, " || "))
+			assert.Equal(t, tt.constraint, constraint.String())
+		})
+	}
+}
+
+func TestConstraint_Check(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{constraint: ">=1.0.0 <2.0.0", version: "1.5.0", want: true},
+		{constraint: ">=1.0.0 <2.0.0", version: "2.0.0", want: false},
+		{constraint: ">=1.0.0 <2.0.0", version: "v1.0.0", want: true},
+		{constraint: "^1.2.3", version: "1.9.9", want: true},
+		{constraint: "^1.2.3", version: "2.0.0-rc.1", want: false},
+		{constraint: "^1.2.3", version: "1.3.0-rc.1", want: false},
+		{constraint: "^1.2.3-rc.1", version: "1.2.3-rc.2", want: true},
+		{constraint: "^1.2.3-rc.1", version: "1.2.3", want: true},
+		{constraint: "^1.2.3-rc.1", version: "1.2.4-rc.1", want: false},
+		{constraint: "~1.2.3", version: "1.2.9", want: true},
+		{constraint: "~1.2.3", version: "1.3.0", want: false},
+		{constraint: "1.2.3 - 2.3.4", version: "2.3.4", want: true},
+		{constraint: "1.2.3 - 2.3", version: "2.3.9", want: true},
+		{constraint: "1.2.3", version: "1.2.3+build.1", want: true},
+		{constraint: "*", version: "1.0.0-rc.1", want: false},
+		{constraint: "<1.0.0 || >=2.0.0", version: "0.9.0", want: true},
+		{constraint: "<1.0.0 || >=2.0.0", version: "1.5.0", want: false},
+		{constraint: ">1.0.0-alpha", version: "1.0.0-beta", want: true},
+		{constraint: ">1.0.0-alpha", version: "1.0.0", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.constraint+" "+tt.version, func(t *testing.T) {
+			constraint, err := ParseConstraint(tt.constraint)
+			require.NoError(t, err)
+			version, err := ParseVersion(tt.version)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, constraint.Check(version))
+		})
+	}
+}
@@ -1,7 +1,23 @@
 package models
 
+import "fmt"
+
 type EmptyVersionListError struct{}
 
 func (e *EmptyVersionListError) Error() string {
 	return "error: version list is empty"
 }
+
+// InvalidVersionError describes an entry of a version list that is not a valid version
+type InvalidVersionError struct {
+	Entry string
+	Err   error
+}
+
+func (e *InvalidVersionError) Error() string {
+	return fmt.Sprintf("invalid version %q: %v", e.Entry, e.Err)
+}
+
+func (e *InvalidVersionError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// VersionPlaceholder marks the position of the version in a namespace template
+const VersionPlaceholder = "{version}"
+
+// Namespace is the template of the tags of a monorepo component, e.g.
+// service-a/v{version} or lib/core@{version}. A template without the
+// version placeholder is a prefix.
+type Namespace struct {
+	Prefix string
+	Suffix string
+}
+
+// ParseNamespace parses a namespace template, an empty template is the
+// empty namespace
+func ParseNamespace(template string) (Namespace, error) {
+	if strings.Count(template, VersionPlaceholder) > 1 {
+		return Namespace{}, fmt.Errorf("namespace %q MUST contain %s at most once", template, VersionPlaceholder)
+	}
+	if strings.IndexFunc(template, func(r rune) bool { return unicode.IsSpace(r) || r == ',' || r == '#' }) != -1 {
+		return Namespace{}, fmt.Errorf("namespace %q MUST NOT contain spaces, commas or #", template)
+	}
+
+	prefix, suffix, _ := strings.Cut(template, VersionPlaceholder)
+	return Namespace{Prefix: prefix, Suffix: suffix}, nil
+}
+
+func (n Namespace) IsEmpty() bool {
+	return n.Prefix == "" && n.Suffix == ""
+}
+
+// Strip returns the version of a tag of the namespace, false is returned
+// for the tags of other namespaces
+func (n Namespace) Strip(tag string) (string, bool) {
+	if len(tag) <= len(n.Prefix)+len(n.Suffix) || !strings.HasPrefix(tag, n.Prefix) || !strings.HasSuffix(tag, n.Suffix) {
+		return "", n.IsEmpty() && tag != ""
+	}
+	return tag[len(n.Prefix) : len(tag)-len(n.Suffix)], true
+}
+
+// HasVersionPrefix reports whether the template writes the VersionPrefix
+// before the version, e.g. service-a/v
+func (n Namespace) HasVersionPrefix() bool {
+	return strings.HasSuffix(n.Prefix, VersionPrefix)
+}
+
+// Apply returns the tag of a version in the namespace
+func (n Namespace) Apply(version string) string {
+	return n.Prefix + version + n.Suffix
+}
+
+func (n Namespace) String() string {
+	if n.IsEmpty() {
+		return ""
+	}
+	return n.Apply(VersionPlaceholder)
+}
@@ -0,0 +1,78 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseNamespace(t *testing.T) {
+	tests := []struct {
+		template string
+		want     Namespace
+		wantErr  bool
+	}{
+		{template: "", want: Namespace{}},
+		{template: "service-a/v", want: Namespace{Prefix: "service-a/v"}},
+		{template: "lib/core@{version}", want: Namespace{Prefix: "lib/core@"}},
+		{template: "app-{version}-linux", want: Namespace{Prefix: "app-", Suffix: "-linux"}},
+		{template: "{version}{version}", wantErr: true},
+		{template: "service a/", wantErr: true},
+		{template: "a,b/", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.template, func(t *testing.T) {
+			got, err := ParseNamespace(tt.template)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNamespace_Strip(t *testing.T) {
+	tests := []struct {
+		namespace string
+		tag       string
+		want      string
+		wantFound bool
+	}{
+		{namespace: "service-a/v", tag: "service-a/v1.4.2", want: "1.4.2", wantFound: true},
+		{namespace: "service-a/v", tag: "service-b/v1.4.2"},
+		{namespace: "service-a/v", tag: "service-a/v"},
+		{namespace: "lib/core@{version}", tag: "lib/core@2.0.0+build.1", want: "2.0.0+build.1", wantFound: true},
+		{namespace: "app-{version}-linux", tag: "app-1.0.0-linux", want: "1.0.0", wantFound: true},
+		{namespace: "app-{version}-linux", tag: "app-1.0.0-darwin"},
+		{namespace: "", tag: "1.0.0", want: "1.0.0", wantFound: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace+" "+tt.tag, func(t *testing.T) {
+			namespace, err := ParseNamespace(tt.namespace)
+			require.NoError(t, err)
+
+			got, found := namespace.Strip(tt.tag)
+			assert.Equal(t, tt.wantFound, found)
+			if tt.wantFound {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestNamespace_Apply(t *testing.T) {
+	namespace, err := ParseNamespace("lib/core@{version}")
+	require.NoError(t, err)
+	assert.Equal(t, "lib/core@2.0.1", namespace.Apply("2.0.1"))
+	assert.Equal(t, "lib/core@{version}", namespace.String())
+	assert.Equal(t, "", Namespace{}.String())
+}
+
+func TestNamespace_HasVersionPrefix(t *testing.T) {
+	assert.True(t, Namespace{Prefix: "service-a/v"}.HasVersionPrefix())
+	assert.False(t, Namespace{Prefix: "lib/core@"}.HasVersionPrefix())
+	assert.False(t, Namespace{}.HasVersionPrefix())
+}
@@ -0,0 +1,47 @@
+package models
+
+import "fmt"
+
+// PrefixStyle selects how the VersionPrefix of versions is printed
+type PrefixStyle string
+
+const (
+	// PrefixKeep prints versions as they were written
+	PrefixKeep PrefixStyle = "keep"
+	// PrefixStrip prints versions without prefix
+	PrefixStrip PrefixStyle = "strip"
+	// PrefixAdd prints every version with the VersionPrefix
+	PrefixAdd PrefixStyle = "add"
+)
+
+// ParsePrefixStyle parses a prefix style, the empty style keeps the prefixes
+func ParsePrefixStyle(style string) (PrefixStyle, error) {
+	switch PrefixStyle(style) {
+	case "", PrefixKeep:
+		return PrefixKeep, nil
+	case PrefixStrip, PrefixAdd:
+		return PrefixStyle(style), nil
+	default:
+		return "", fmt.Errorf("invalid prefix style %q, options: %s, %s, %s", style, PrefixKeep, PrefixStrip, PrefixAdd)
+	}
+}
+
+// Apply returns the version written in the style
+func (s PrefixStyle) Apply(version Version) Version {
+	switch s {
+	case PrefixStrip:
+		version.Prefix = ""
+	case PrefixAdd:
+		version.Prefix = VersionPrefix
+	}
+	return version
+}
+
+// ApplyAll returns the versions written in the style
+func (s PrefixStyle) ApplyAll(versions []Version) VersionSlice {
+	styled := make(VersionSlice, 0, len(versions))
+	for _, version := range versions {
+		styled = append(styled, s.Apply(version))
+	}
+	return styled
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrefixStyle_Apply(t *testing.T) {
+	prefixed, err := ParseVersion("v1.2.3")
+	require.NoError(t, err)
+	bare, err := ParseVersion("1.2.4-rc.1")
+	require.NoError(t, err)
+	versions := []Version{prefixed, bare}
+
+	tests := []struct {
+		style string
+		want  string
+	}{
+		{style: "", want: "v1.2.3 1.2.4-rc.1"},
+		{style: "keep", want: "v1.2.3 1.2.4-rc.1"},
+		{style: "strip", want: "1.2.3 1.2.4-rc.1"},
+		{style: "add", want: "v1.2.3 v1.2.4-rc.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			style, err := ParsePrefixStyle(tt.style)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, style.ApplyAll(versions).String())
+		})
+	}
+
+	_, err = ParsePrefixStyle("upper")
+	assert.ErrorContains(t, err, `invalid prefix style "upper"`)
+}
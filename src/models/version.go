@@ -72,7 +72,13 @@ func (r ReleaseDigit) Value() uint64 {
 	return r.value
 }
 
+// VersionPrefix is the prefix commonly written before a version in tags, e.g. v1.2.3
+const VersionPrefix = "v"
+
 type Version struct {
+	// Prefix is the VersionPrefix the version was written with, if any,
+	// it is ignored in comparisons
+	Prefix        string
 	Release       Release
 	Prerelease    PRVersion
 	BuildMetadata BuildMetadata
@@ -99,6 +105,10 @@ func (v Version) IsHigherThan(versionB Version) bool {
 		return true
 	}
 
+	if !v.Release.IsEqualTo(versionB.Release) {
+		return false
+	}
+
 	if v.IsRelease() && !versionB.IsRelease() {
 		return true
 	}
@@ -111,11 +121,17 @@ func (v Version) IsHigherThan(versionB Version) bool {
 }
 
 func (v *Version) String() string {
-	version := fmt.Sprintf("%s%s%s", v.Release.String(), v.Prerelease.String(), v.BuildMetadata.String())
+	version := fmt.Sprintf("%s%s%s%s", v.Prefix, v.Release.String(), v.Prerelease.String(), v.BuildMetadata.String())
 	return version
 }
 
+// ParseVersion parses a Semver version, optionally written with the
+// VersionPrefix which is kept in the Prefix of the version
 func ParseVersion(v string) (Version, error) {
+	prefix := ""
+	if strings.HasPrefix(v, VersionPrefix) {
+		prefix, v = VersionPrefix, strings.TrimPrefix(v, VersionPrefix)
+	}
 
 	rRelease, rPrerelease, rBuildMetadata := GetVersionComponents(v)
 	release, err := ParseRelease(rRelease)
@@ -145,6 +161,7 @@ func ParseVersion(v string) (Version, error) {
 	}
 
 	return Version{
+			Prefix:        prefix,
 			Release:       release,
 			Prerelease:    prVersion,
 			BuildMetadata: buildMetadata,
@@ -187,6 +204,19 @@ func GetBuildMetadata(v string) string {
 
 type VersionSlice []Version
 
+func (vs VersionSlice) Len() int {
+	return len(vs)
+}
+
+// Less orders versions by ascending Semver precedence
+func (vs VersionSlice) Less(i, j int) bool {
+	return vs[j].IsHigherThan(vs[i])
+}
+
+func (vs VersionSlice) Swap(i, j int) {
+	vs[i], vs[j] = vs[j], vs[i]
+}
+
 func (vs VersionSlice) String() string {
 	var builder strings.Builder
 
@@ -237,6 +267,10 @@ func ParseRelease(v string) (Release, error) {
 	release := strings.SplitN(v, "-", 2)[0]
 	release = strings.SplitN(release, "+", 2)[0]
 
+	if len(strings.Split(release, ".")) != 3 {
+		return Release{}, fmt.Errorf("release MUST comprise exactly three dot-separated identifiers MAJOR.MINOR.PATCH, got: %s", release)
+	}
+
 	majorUint, err := parseMajor(release)
 	if err != nil {
 		return Release{}, err
@@ -393,6 +427,22 @@ func (bm *BuildMetadata) String() string {
 	return ""
 }
 
+// IsHigherThan compares the identifiers of two BuildMetadata like prerelease
+// identifiers, the empty build metadata being the lowest. Build metadata
+// has no precedence in Semver, this only orders versions of equal precedence.
+func (bm *BuildMetadata) IsHigherThan(bmB BuildMetadata) bool {
+	for index, identifier := range bm.Identifiers {
+		if len(bmB.Identifiers) <= index {
+			return true
+		}
+		prIdentifier, prIdentifierB := PRIdentifier{identifier.identifier}, PRIdentifier{bmB.Identifiers[index].identifier}
+		if !prIdentifier.IsEqualTo(prIdentifierB) {
+			return prIdentifier.IsHigherThan(prIdentifierB)
+		}
+	}
+	return false
+}
+
 type PRIdentifier struct {
 	identifier string
 }
@@ -408,11 +458,20 @@ func (i PRIdentifier) IsEqualTo(identifierB PRIdentifier) bool {
 
 // IsHigherThan compares two PRIdentifiers and returns true if the first identifier is higher than the second
 // according to the Semver specification
+// Numeric identifiers always have lower precedence than alphanumeric identifiers
 func (i PRIdentifier) IsHigherThan(identifierB PRIdentifier) bool {
-	if containsOnly(i.identifier, numbers) && containsOnly(identifierB.identifier, numbers) {
-		iValue, _ := strconv.ParseUint(i.identifier, 10, 64)
-		identifierBValue, _ := strconv.ParseUint(identifierB.identifier, 10, 64)
-		return iValue > identifierBValue
+	iIsNumeric := containsOnly(i.identifier, numbers)
+	identifierBIsNumeric := containsOnly(identifierB.identifier, numbers)
+
+	if iIsNumeric && identifierBIsNumeric {
+		if len(i.identifier) != len(identifierB.identifier) {
+			return len(i.identifier) > len(identifierB.identifier)
+		}
+		return i.identifier > identifierB.identifier
+	}
+
+	if iIsNumeric != identifierBIsNumeric {
+		return identifierBIsNumeric
 	}
 	return i.identifier > identifierB.identifier
 }
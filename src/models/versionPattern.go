@@ -2,10 +2,17 @@ package models
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 )
 
-const Wildcard = "*"
+const (
+	Wildcard = "*"
+	// RestWildcard matches any number of remaining prerelease or build
+	// metadata identifiers, it may only be the last identifier of a pattern
+	RestWildcard = "**"
+)
 
 type VersionPattern struct {
 	Release    ReleasePattern
@@ -53,22 +60,23 @@ func (v VersionPattern) FirstRelease() (FirstRelease Release) {
 }
 
 func (v VersionPattern) FirstPrerelease() PRVersion {
-	var rawPRVersion string
-	for i, identifier := range v.Prerelease.Identifiers {
-		rawId := getAbsoluteValue(identifier.pattern)
-		if i == 0 {
-			rawPRVersion = fmt.Sprintf("%s", rawId)
-		} else {
-			rawPRVersion = fmt.Sprintf("%s.%s", rawPRVersion, rawId)
+	rawIds := []string{}
+	for _, identifier := range v.Prerelease.Identifiers {
+		if identifier.pattern.value == RestWildcard {
+			continue
 		}
+		rawIds = append(rawIds, getAbsoluteValue(identifier.pattern))
 	}
-	prVersion, _ := ParsePRVersion(rawPRVersion)
+	prVersion, _ := ParsePRVersion(strings.Join(rawIds, "."))
 	return prVersion
 }
 
 func (v VersionPattern) FirstBuildMetadata() BuildMetadata {
 	var rawBuildMetadata string
 	for i, buildId := range v.Build.Identifiers {
+		if buildId.pattern.value == RestWildcard {
+			continue
+		}
 		rawId := getAbsoluteValue(buildId.pattern)
 		if i == 0 {
 			rawBuildMetadata = fmt.Sprintf("%s+%s", rawBuildMetadata, rawId)
@@ -80,26 +88,73 @@ func (v VersionPattern) FirstBuildMetadata() BuildMetadata {
 	return buildMetadata
 }
 
+// getAbsoluteValue returns the lowest identifier matching a pattern, 0 for
+// a wildcard, 2 for [2-5] or {4,2} and rc for rc*
 func getAbsoluteValue(patten Pattern) string {
-	if patten.value == Wildcard {
+	switch {
+	case patten.value == Wildcard:
 		return "0"
+	case isRangePattern(patten.value):
+		low, _, _ := parseRangePattern(patten.value)
+		return strconv.FormatUint(low, 10)
+	case isSetPattern(patten.value):
+		members := setMembers(patten.value)
+		lowest := members[0]
+		for _, member := range members[1:] {
+			if isLowerIdentifier(member, lowest) {
+				lowest = member
+			}
+		}
+		return lowest
+	case strings.HasSuffix(patten.value, Wildcard):
+		return strings.TrimSuffix(patten.value, Wildcard)
 	}
 	return patten.value
 }
 
+// isLowerIdentifier compares two identifiers by their Semver precedence,
+// numeric identifiers are lower than alphanumeric ones
+func isLowerIdentifier(a, b string) bool {
+	aNumber, aErr := strconv.ParseUint(a, 10, 64)
+	bNumber, bErr := strconv.ParseUint(b, 10, 64)
+	switch {
+	case aErr == nil && bErr == nil:
+		return aNumber < bNumber
+	case aErr == nil || bErr == nil:
+		return aErr == nil
+	}
+	return a < b
+}
+
+// ParseVersionPattern parses a pattern of versions. Every identifier is a
+// literal, the * wildcard (x and X are accepted for the release), a numeric
+// range [2-5] or a set {2,4}. Prerelease and build identifiers may also end
+// with a prefix wildcard e.g. rc*, and the last of them may be ** to match
+// any number of remaining identifiers, e.g. 1.[2-5].*, 1.{2,4}.x,
+// *.*.*-rc* or *.*.*-rc.**.
 func ParseVersionPattern(pattern string) (VersionPattern, error) {
-	release, err := parseReleasePattern(pattern)
+	rest, rawBuildMetadata, hasBuildMetadata := cutOutsideBrackets(pattern, '+')
+	rawRelease, rawPrerelease, hasPrerelease := cutOutsideBrackets(rest, '-')
+
+	release, err := parseReleasePattern(rawRelease)
 	if err != nil {
 		return VersionPattern{}, err
 	}
-	prerelease, err := parsePrereleasePattern(pattern)
-	if err != nil {
-		return VersionPattern{}, err
+
+	prerelease := PRVersionPattern{}
+	if hasPrerelease {
+		prerelease, err = parsePrereleasePattern(rawPrerelease)
+		if err != nil {
+			return VersionPattern{}, err
+		}
 	}
 
-	buildMetadata, err := parseBuildMetadataPattern(pattern)
-	if err != nil {
-		return VersionPattern{}, err
+	buildMetadata := BuildMetadataPattern{}
+	if hasBuildMetadata {
+		buildMetadata, err = parseBuildMetadataPattern(rawBuildMetadata)
+		if err != nil {
+			return VersionPattern{}, err
+		}
 	}
 	return VersionPattern{
 		Release:    release,
@@ -108,16 +163,46 @@ func ParseVersionPattern(pattern string) (VersionPattern, error) {
 	}, nil
 }
 
-func parsePrereleasePattern(pattern string) (PRVersionPattern, error) {
-	tokens := strings.SplitN(pattern, "+", 2)
-	tokens = strings.SplitN(tokens[0], "-", 2)
-	if len(tokens) < 2 {
-		return PRVersionPattern{}, nil
+// ParseVersionPatterns parses every pattern of a list, e.g. the repeated
+// values of a flag
+func ParseVersionPatterns(patterns []string) ([]VersionPattern, error) {
+	versionPatterns := []VersionPattern{}
+	for _, pattern := range patterns {
+		versionPattern, err := ParseVersionPattern(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		versionPatterns = append(versionPatterns, versionPattern)
+	}
+	return versionPatterns, nil
+}
+
+// cutOutsideBrackets slices s around the first separator that is not inside
+// a [range] or a {set}
+func cutOutsideBrackets(s string, separator byte) (before, after string, found bool) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth = max(depth-1, 0)
+		case separator:
+			if depth == 0 {
+				return s[:i], s[i+1:], true
+			}
+		}
 	}
-	prereleasePattern := tokens[1]
-	identifiersPattern := strings.Split(prereleasePattern, ".")
+	return s, "", false
+}
+
+func parsePrereleasePattern(pattern string) (PRVersionPattern, error) {
+	identifiersPattern := strings.Split(pattern, ".")
 	var prIdentifiersPattern []PRIdentifierPattern
-	for _, identifierPattern := range identifiersPattern {
+	for i, identifierPattern := range identifiersPattern {
+		if identifierPattern == RestWildcard && i != len(identifiersPattern)-1 {
+			return PRVersionPattern{}, fmt.Errorf("%s MUST be the last prerelease identifier, got: %s", RestWildcard, pattern)
+		}
 		p, err := parsePrIdentifierPattern(identifierPattern)
 		if err != nil {
 			return PRVersionPattern{}, err
@@ -136,103 +221,74 @@ func parsePrIdentifierPattern(pattern string) (PRIdentifierPattern, error) {
 }
 
 func (i *PRIdentifierPattern) Set(pattern string) error {
-	if len(pattern) < 1 {
-		return fmt.Errorf("prerelease identifiers MUST NOT be empty, got: %s", pattern)
+	p, err := parseIdentifierPattern(pattern, "prerelease identifiers", true, validPrIdentifier)
+	if err != nil {
+		return err
 	}
-	if containsOnly(pattern, numbers) {
-		if len(pattern) > 1 && pattern[0] == '0' {
-			return fmt.Errorf("prerelease numeric identifiers MUST NOT include leading zeros, got: %s", pattern)
-		}
-		i.pattern = Pattern{value: pattern}
-		return nil
+	i.pattern = p
+	return nil
+}
+
+func validPrIdentifier(identifier string) error {
+	if len(identifier) < 1 {
+		return fmt.Errorf("prerelease identifiers MUST NOT be empty, got: %s", identifier)
 	}
-	if containsOnly(pattern, alphanum) {
-		i.pattern = Pattern{value: pattern}
+	if containsOnly(identifier, numbers) {
+		if len(identifier) > 1 && identifier[0] == '0' {
+			return fmt.Errorf("prerelease numeric identifiers MUST NOT include leading zeros, got: %s", identifier)
+		}
 		return nil
 	}
-
-	if pattern == Wildcard {
-		i.pattern = Pattern{value: pattern}
+	if containsOnly(identifier, alphanum) {
 		return nil
 	}
-	return fmt.Errorf("prerelease identifiers MUST contain only alphanumerics and hyphens, got: %s", pattern)
+	return fmt.Errorf("prerelease identifiers MUST contain only alphanumerics and hyphens, got: %s", identifier)
 }
 
 func parseReleasePattern(pattern string) (ReleasePattern, error) {
-	release := strings.SplitN(pattern, "-", 2)[0]
-	release = strings.SplitN(release, "+", 2)[0]
-	major, err := parseMajorPattern(release)
+	identifiers := strings.Split(pattern, ".")
+	if len(identifiers) != 3 {
+		return ReleasePattern{}, fmt.Errorf("release pattern MUST have three dot-separated identifiers e.g. 1.*.*, got: %s", pattern)
+	}
+
+	major, err := parseDigitsPattern(identifiers[0], Major)
 	if err != nil {
 		return ReleasePattern{}, err
 	}
 
-	minor, err := parseMinorPattern(release)
+	minor, err := parseDigitsPattern(identifiers[1], Minor)
 	if err != nil {
 		return ReleasePattern{}, err
 	}
 
-	patch, err := parsePatchPattern(release)
+	patch, err := parseDigitsPattern(identifiers[2], Patch)
 	if err != nil {
 		return ReleasePattern{}, err
 	}
 
 	return ReleasePattern{
-		Major: major,
-		Minor: minor,
-		Patch: patch,
+		Major: ReleaseDigitPattern{pattern: major},
+		Minor: ReleaseDigitPattern{pattern: minor},
+		Patch: ReleaseDigitPattern{pattern: patch},
 	}, nil
 }
 
-func parseMajorPattern(pattern string) (ReleaseDigitPattern, error) {
-	tokens := strings.SplitN(pattern, ".", 2)
-	majorPattern := tokens[0]
-	p, err := parseDigitsPattern(majorPattern, Major)
-	if err != nil {
-		return ReleaseDigitPattern{}, err
-	}
-	return ReleaseDigitPattern{pattern: p}, nil
-}
-
-func parseMinorPattern(pattern string) (ReleaseDigitPattern, error) {
-	tokens := strings.SplitN(pattern, ".", 3)
-	minor := tokens[1]
-	p, err := parseDigitsPattern(minor, Minor)
-	if err != nil {
-		return ReleaseDigitPattern{}, err
-	}
-	return ReleaseDigitPattern{pattern: p}, nil
-}
-
-func parsePatchPattern(pattern string) (ReleaseDigitPattern, error) {
-	tokens := strings.SplitN(pattern, ".", 3)
-	patch := tokens[2]
-	p, err := parseDigitsPattern(patch, Patch)
-	if err != nil {
-		return ReleaseDigitPattern{}, err
-	}
-	return ReleaseDigitPattern{pattern: p}, nil
-}
-
 func parseDigitsPattern(pattern string, increment Increment) (Pattern, error) {
-	if err := versionDigitsCompliance(pattern, increment); err != nil {
-		if pattern == Wildcard {
-			return Pattern{value: pattern}, nil
-		} else {
-			return Pattern{}, err
-		}
+	if pattern == "x" || pattern == "X" {
+		pattern = Wildcard
 	}
-	return Pattern{value: pattern}, nil
+	return parseIdentifierPattern(pattern, string(increment), false, func(identifier string) error {
+		return versionDigitsCompliance(identifier, increment)
+	})
 }
 
 func parseBuildMetadataPattern(pattern string) (BuildMetadataPattern, error) {
-	tokens := strings.SplitN(pattern, "+", 2)
-	if len(tokens) < 2 {
-		return BuildMetadataPattern{}, nil
-	}
-	buildMetadataPattern := tokens[1]
-	identifiersPattern := strings.Split(buildMetadataPattern, ".")
+	identifiersPattern := strings.Split(pattern, ".")
 	var buildIdentifiersPattern []BuildIdentifierPattern
-	for _, identifierPattern := range identifiersPattern {
+	for i, identifierPattern := range identifiersPattern {
+		if identifierPattern == RestWildcard && i != len(identifiersPattern)-1 {
+			return BuildMetadataPattern{}, fmt.Errorf("%s MUST be the last build identifier, got: %s", RestWildcard, pattern)
+		}
 		p, err := parseBuildIdentifierPattern(identifierPattern)
 		if err != nil {
 			return BuildMetadataPattern{}, err
@@ -251,18 +307,100 @@ func parseBuildIdentifierPattern(pattern string) (BuildIdentifierPattern, error)
 }
 
 func (i *BuildIdentifierPattern) Set(pattern string) error {
-	if len(pattern) < 1 {
-		return fmt.Errorf("build identifiers MUST NOT be empty, got: %s", pattern)
+	p, err := parseIdentifierPattern(pattern, "build identifiers", true, validBuildIdentifier)
+	if err != nil {
+		return err
 	}
-	if containsOnly(pattern, alphanum) {
-		i.pattern = Pattern{value: pattern}
-		return nil
+	i.pattern = p
+	return nil
+}
+
+func validBuildIdentifier(identifier string) error {
+	if len(identifier) < 1 {
+		return fmt.Errorf("build identifiers MUST NOT be empty, got: %s", identifier)
 	}
-	if pattern == Wildcard {
-		i.pattern = Pattern{value: pattern}
+	if containsOnly(identifier, alphanum) {
 		return nil
 	}
-	return fmt.Errorf("build identifiers MUST contain only alphanumerics and hyphens, got: %s", pattern)
+	return fmt.Errorf("build identifiers MUST contain only alphanumerics and hyphens, got: %s", identifier)
+}
+
+// parseIdentifierPattern parses the pattern of an identifier, the literals
+// are checked with valid. Prefix wildcards and ** are only accepted with
+// prefixes, in prerelease and build identifiers.
+func parseIdentifierPattern(pattern string, name string, prefixes bool, valid func(string) error) (Pattern, error) {
+	switch {
+	case pattern == Wildcard:
+	case pattern == RestWildcard && prefixes:
+	case isRangePattern(pattern):
+		if _, _, err := parseRangePattern(pattern); err != nil {
+			return Pattern{}, fmt.Errorf("%s: %w", name, err)
+		}
+	case strings.HasPrefix(pattern, "[") || strings.HasPrefix(pattern, "{"):
+		if !strings.HasSuffix(pattern, "]") && !strings.HasSuffix(pattern, "}") {
+			return Pattern{}, fmt.Errorf("%s pattern %s is missing its closing bracket", name, pattern)
+		}
+		if !isSetPattern(pattern) {
+			return Pattern{}, fmt.Errorf("%s pattern %s MUST be a range [LOW-HIGH] or a set {A,B}", name, pattern)
+		}
+		members := setMembers(pattern)
+		for _, member := range members {
+			if err := valid(member); err != nil {
+				return Pattern{}, fmt.Errorf("set %s: %w", pattern, err)
+			}
+		}
+	case strings.Contains(pattern, Wildcard):
+		prefix := strings.TrimSuffix(pattern, Wildcard)
+		if !prefixes || strings.Contains(prefix, Wildcard) {
+			return Pattern{}, fmt.Errorf("%s wildcards MUST be a whole identifier or a prefix wildcard such as rc* in prerelease and build identifiers, got: %s", name, pattern)
+		}
+		if err := valid(prefix); err != nil {
+			return Pattern{}, err
+		}
+	default:
+		if err := valid(pattern); err != nil {
+			return Pattern{}, err
+		}
+	}
+	return Pattern{value: pattern}, nil
+}
+
+func isRangePattern(pattern string) bool {
+	return strings.HasPrefix(pattern, "[") && strings.HasSuffix(pattern, "]")
+}
+
+// parseRangePattern returns the bounds of a numeric range such as [2-5]
+func parseRangePattern(pattern string) (low, high uint64, err error) {
+	rawLow, rawHigh, found := strings.Cut(strings.TrimSuffix(strings.TrimPrefix(pattern, "["), "]"), "-")
+	if !found {
+		return 0, 0, fmt.Errorf("range %s MUST be written [LOW-HIGH] e.g. [2-5]", pattern)
+	}
+	for _, bound := range []string{rawLow, rawHigh} {
+		if bound == "" || !containsOnly(bound, numbers) || len(bound) > 1 && bound[0] == '0' {
+			return 0, 0, fmt.Errorf("range %s bounds MUST be numbers without leading zeros, got: %q", pattern, bound)
+		}
+	}
+	low, err = strconv.ParseUint(rawLow, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("range %s: %w", pattern, err)
+	}
+	high, err = strconv.ParseUint(rawHigh, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("range %s: %w", pattern, err)
+	}
+	if low > high {
+		return 0, 0, fmt.Errorf("range %s MUST NOT have its low bound above its high bound", pattern)
+	}
+	return low, high, nil
+}
+
+func isSetPattern(pattern string) bool {
+	return strings.HasPrefix(pattern, "{") && strings.HasSuffix(pattern, "}")
+}
+
+// setMembers returns the identifiers of a set such as {2,4}
+func setMembers(pattern string) []string {
+	return strings.Split(strings.TrimSuffix(strings.TrimPrefix(pattern, "{"), "}"), ",")
 }
 
 type ReleasePattern struct {
@@ -275,11 +413,16 @@ func (r ReleasePattern) String() string {
 	return fmt.Sprintf("%s.%s.%s", r.Major.Value(), r.Minor.Value(), r.Patch.Value())
 }
 
+// IsStrict returns true if the pattern matches a single release
 func (r ReleasePattern) IsStrict() bool {
-	if r.Major.Value() == Wildcard || r.Minor.Value() == Wildcard || r.Patch.Value() == Wildcard {
-		return false
-	}
-	return true
+	return r.Major.pattern.IsLiteral() && r.Minor.pattern.IsLiteral() && r.Patch.pattern.IsLiteral()
+}
+
+// Match returns true if the release matches the pattern
+func (r ReleasePattern) Match(release Release) bool {
+	return r.Major.Match(release.Major.String()) &&
+		r.Minor.Match(release.Minor.String()) &&
+		r.Patch.Match(release.Patch.String())
 }
 
 type ReleaseDigitPattern struct {
@@ -290,10 +433,29 @@ func (m ReleaseDigitPattern) Value() string {
 	return m.pattern.value
 }
 
+// Match returns true if the release identifier matches the pattern
+func (m ReleaseDigitPattern) Match(identifier string) bool {
+	return m.pattern.Match(identifier)
+}
+
 type PRVersionPattern struct {
 	Identifiers []PRIdentifierPattern
 }
 
+// Match returns true if the prerelease identifiers match the pattern one by
+// one. A release only matches the empty pattern.
+func (p PRVersionPattern) Match(prerelease PRVersion) bool {
+	patterns := []Pattern{}
+	for _, identifier := range p.Identifiers {
+		patterns = append(patterns, identifier.pattern)
+	}
+	identifiers := []string{}
+	for _, identifier := range prerelease.Identifiers {
+		identifiers = append(identifiers, identifier.Value())
+	}
+	return matchIdentifiers(patterns, identifiers)
+}
+
 type PRIdentifierPattern struct {
 	pattern Pattern
 }
@@ -302,10 +464,28 @@ func (p PRIdentifierPattern) Value() string {
 	return p.pattern.value
 }
 
+// Match returns true if the prerelease identifier matches the pattern
+func (p PRIdentifierPattern) Match(identifier string) bool {
+	return p.pattern.Match(identifier)
+}
+
 type BuildMetadataPattern struct {
 	Identifiers []BuildIdentifierPattern
 }
 
+// Match returns true if the build identifiers match the pattern one by one
+func (b BuildMetadataPattern) Match(buildMetadata BuildMetadata) bool {
+	patterns := []Pattern{}
+	for _, identifier := range b.Identifiers {
+		patterns = append(patterns, identifier.pattern)
+	}
+	identifiers := []string{}
+	for _, identifier := range buildMetadata.Identifiers {
+		identifiers = append(identifiers, identifier.String())
+	}
+	return matchIdentifiers(patterns, identifiers)
+}
+
 type BuildIdentifierPattern struct {
 	pattern Pattern
 }
@@ -314,6 +494,63 @@ func (p BuildIdentifierPattern) Value() string {
 	return p.pattern.value
 }
 
+// Match returns true if the build identifier matches the pattern
+func (p BuildIdentifierPattern) Match(identifier string) bool {
+	return p.pattern.Match(identifier)
+}
+
+// Pattern is the pattern of a single identifier
 type Pattern struct {
 	value string
 }
+
+// Match returns true if the identifier matches the pattern
+func (p Pattern) Match(identifier string) bool {
+	switch {
+	case p.value == Wildcard || p.value == RestWildcard:
+		return true
+	case isRangePattern(p.value):
+		low, high, _ := parseRangePattern(p.value)
+		if identifier == "" || !containsOnly(identifier, numbers) {
+			return false
+		}
+		number, err := strconv.ParseUint(identifier, 10, 64)
+		return err == nil && number >= low && number <= high
+	case isSetPattern(p.value):
+		return slices.Contains(setMembers(p.value), identifier)
+	case strings.HasSuffix(p.value, Wildcard):
+		return strings.HasPrefix(identifier, strings.TrimSuffix(p.value, Wildcard))
+	}
+	return p.value == identifier
+}
+
+// IsLiteral returns true if the pattern only matches itself
+func (p Pattern) IsLiteral() bool {
+	return !strings.ContainsAny(p.value, "*[{")
+}
+
+// matchIdentifiers returns true if the identifiers match the patterns one by
+// one, a trailing ** matching any number of remaining identifiers. Non-empty
+// patterns never match an empty list of identifiers.
+func matchIdentifiers(patterns []Pattern, identifiers []string) bool {
+	if len(patterns) > 0 && len(identifiers) == 0 {
+		return false
+	}
+
+	rest := len(patterns) > 0 && patterns[len(patterns)-1].value == RestWildcard
+	if rest {
+		patterns = patterns[:len(patterns)-1]
+		if len(identifiers) < len(patterns) {
+			return false
+		}
+	} else if len(identifiers) != len(patterns) {
+		return false
+	}
+
+	for i, pattern := range patterns {
+		if !pattern.Match(identifiers[i]) {
+			return false
+		}
+	}
+	return true
+}
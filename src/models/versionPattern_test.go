@@ -224,6 +224,134 @@ func TestParseVersionPattern(t *testing.T) {
 	}
 }
 
+func TestParseVersionPatternSyntax(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		matches   []string
+		others    []string
+		first     string
+		expectErr string
+	}{
+		{
+			name:    "Numeric range",
+			pattern: "1.[2-5].*",
+			matches: []string{"1.2.0", "1.5.9"},
+			others:  []string{"1.1.0", "1.6.0", "1.3.0-rc.1"},
+			first:   "1.2.0",
+		},
+		{
+			name:    "Set and x wildcard",
+			pattern: "1.{4,2}.x",
+			matches: []string{"1.2.0", "1.4.3"},
+			others:  []string{"1.3.0", "2.2.0"},
+			first:   "1.2.0",
+		},
+		{
+			name:    "Prefix wildcard",
+			pattern: "*.*.*-rc*",
+			matches: []string{"1.0.0-rc", "1.0.0-rc2"},
+			others:  []string{"1.0.0", "1.0.0-rc.2", "1.0.0-beta"},
+			first:   "0.0.0-rc",
+		},
+		{
+			name:    "Trailing rest wildcard",
+			pattern: "2.0.0-beta.**",
+			matches: []string{"2.0.0-beta", "2.0.0-beta.1", "2.0.0-beta.1.x"},
+			others:  []string{"2.0.0", "2.0.0-alpha.1"},
+			first:   "2.0.0-beta",
+		},
+		{
+			name:    "Prerelease range and set",
+			pattern: "1.0.0-{alpha,beta}.[1-3]",
+			matches: []string{"1.0.0-alpha.1", "1.0.0-beta.3"},
+			others:  []string{"1.0.0-rc.1", "1.0.0-beta.4", "1.0.0-beta.x"},
+			first:   "1.0.0-alpha.1",
+		},
+		{
+			name:    "Build metadata rest wildcard",
+			pattern: "1.0.0+build.**",
+			matches: []string{"1.0.0+build", "1.0.0+build.7.sha"},
+			others:  []string{"1.0.0", "1.0.0+ci.7"},
+		},
+		{
+			name:      "Incomplete release",
+			pattern:   "1.*",
+			expectErr: "release pattern MUST have three dot-separated identifiers",
+		},
+		{
+			name:      "Unclosed range",
+			pattern:   "1.[2-5.*",
+			expectErr: "is missing its closing bracket",
+		},
+		{
+			name:      "Reversed range",
+			pattern:   "1.[5-2].*",
+			expectErr: "MUST NOT have its low bound above its high bound",
+		},
+		{
+			name:      "Range without bounds",
+			pattern:   "1.[2].*",
+			expectErr: "MUST be written [LOW-HIGH]",
+		},
+		{
+			name:      "Invalid set member",
+			pattern:   "1.{2,a}.*",
+			expectErr: "set {2,a}: minor MUST comprise only ASCII numerics",
+		},
+		{
+			name:      "Prefix wildcard in the release",
+			pattern:   "1*.*.*",
+			expectErr: "wildcards MUST be a whole identifier",
+		},
+		{
+			name:      "Rest wildcard before the last identifier",
+			pattern:   "*.*.*-**.rc",
+			expectErr: "** MUST be the last prerelease identifier",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pattern, err := ParseVersionPattern(tt.pattern)
+			if tt.expectErr != "" {
+				assert.ErrorContains(t, err, tt.expectErr)
+				return
+			}
+			assert.NoError(t, err)
+
+			match := func(rawVersion string) bool {
+				version := newVersion(rawVersion)
+				return pattern.Release.Match(version.Release) && pattern.Prerelease.Match(version.Prerelease) &&
+					(len(pattern.Build.Identifiers) == 0 || pattern.Build.Match(version.BuildMetadata))
+			}
+			for _, rawVersion := range tt.matches {
+				assert.True(t, match(rawVersion), rawVersion)
+			}
+			for _, rawVersion := range tt.others {
+				assert.False(t, match(rawVersion), rawVersion)
+			}
+			if tt.first != "" {
+				first := pattern.FirstVersion()
+				assert.Equal(t, tt.first, first.String())
+			}
+		})
+	}
+}
+
+func TestParseVersionPatterns(t *testing.T) {
+	patterns, err := ParseVersionPatterns([]string{"2.0.*", "*.*.*-nightly.*"})
+	assert.NoError(t, err)
+	assert.Equal(t, []VersionPattern{newVersionPattern("2.0.*"), newVersionPattern("*.*.*-nightly.*")}, patterns)
+
+	patterns, err = ParseVersionPatterns(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, patterns)
+
+	_, err = ParseVersionPatterns([]string{"2.0.*", "1.2.3-!"})
+	assert.ErrorContains(t, err, `invalid pattern "1.2.3-!"`)
+}
+
 func newVersionPattern(s string) VersionPattern {
 	v, _ := ParseVersionPattern(s)
 	return v
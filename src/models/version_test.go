@@ -148,6 +148,33 @@ func TestParseVersion(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:  "Valid Version with v prefix",
+			input: "v1.0.0-beta",
+			want: Version{
+				Prefix: "v",
+				Release: Release{
+					Major: ReleaseDigit{value: 1},
+					Minor: ReleaseDigit{value: 0},
+					Patch: ReleaseDigit{value: 0},
+				},
+				Prerelease:    PRVersion{Identifiers: []PRIdentifier{{identifier: "beta"}}},
+				BuildMetadata: BuildMetadata{},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "Invalid Version with v prefix only",
+			input:   "v",
+			want:    Version{},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Version with double v prefix",
+			input:   "vv1.0.0",
+			want:    Version{},
+			wantErr: true,
+		},
 		{
 			name:    "Invalid patch version",
 			input:   "1.0.a",
@@ -238,6 +265,18 @@ func TestParseVersion(t *testing.T) {
 			want:    Version{},
 			wantErr: true,
 		},
+		{
+			name:    "Invalid release missing patch",
+			input:   "1.2",
+			want:    Version{},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid release with extra identifier",
+			input:   "1.2.3.4",
+			want:    Version{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -451,6 +490,46 @@ func TestVersion_IsHigherThan(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "Prerelease alphanumeric identifier higher than numeric identifier",
+			v:    "1.0.0-alpha.0valid",
+			args: args{
+				versionB: "1.0.0-alpha.1",
+			},
+			want: true,
+		},
+		{
+			name: "Prerelease numeric identifier lower than hyphen identifier",
+			v:    "1.0.0-1",
+			args: args{
+				versionB: "1.0.0--",
+			},
+			want: false,
+		},
+		{
+			name: "Prerelease numeric identifiers beyond uint64",
+			v:    "1.0.0-99999999999999999999",
+			args: args{
+				versionB: "1.0.0-9999999999999999999",
+			},
+			want: true,
+		},
+		{
+			name: "Release version lower than prerelease of a higher release",
+			v:    "1.0.0",
+			args: args{
+				versionB: "2.0.0-alpha",
+			},
+			want: false,
+		},
+		{
+			name: "Prerelease version lower than prerelease of a higher release",
+			v:    "1.0.0-beta",
+			args: args{
+				versionB: "2.0.0-alpha",
+			},
+			want: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -461,3 +540,25 @@ func TestVersion_IsHigherThan(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildMetadata_IsHigherThan(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want bool
+	}{
+		{name: "Build metadata higher than none", a: "1.0.0+a", b: "1.0.0", want: true},
+		{name: "No build metadata lower", a: "1.0.0", b: "1.0.0+a", want: false},
+		{name: "Numeric identifiers compared numerically", a: "1.0.0+11", b: "1.0.0+2", want: true},
+		{name: "Alphanumeric identifiers higher than numeric", a: "1.0.0+a", b: "1.0.0+2", want: true},
+		{name: "More identifiers higher", a: "1.0.0+a.1", b: "1.0.0+a", want: true},
+		{name: "Same build metadata not higher", a: "1.0.0+a.1", b: "1.0.0+a.1", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := newVersion(tt.a), newVersion(tt.b)
+			assert.Equal(t, tt.want, a.BuildMetadata.IsHigherThan(b.BuildMetadata))
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog"
+)
+
+const fileExtension = ".json"
+
+// Cache stores the tag lists fetched from the HTTP datasources on disk,
+// with one file per platform, owner, repository and credentials
+type Cache struct {
+	Dir string
+	// TTL is how long a stored response is served without revalidating it,
+	// responses are always revalidated with a conditional request when zero
+	TTL time.Duration
+	now func() time.Time
+}
+
+// Key identifies the datasource a cache file belongs to. Credential is the
+// CredentialHash of the credentials the responses were fetched with, so that
+// a response only readable with a token is never served to another one.
+type Key struct {
+	Platform   string `json:"platform"`
+	Owner      string `json:"owner"`
+	Repository string `json:"repository"`
+	Credential string `json:"credential,omitempty"`
+}
+
+// entry is a stored response with the validators to revalidate it
+type entry struct {
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+	Header       http.Header `json:"header,omitempty"`
+	Body         []byte      `json:"body"`
+	ValidatedAt  time.Time   `json:"validatedAt"`
+}
+
+type cacheFile struct {
+	Key     Key               `json:"key"`
+	Entries map[string]*entry `json:"entries"`
+}
+
+// storedHeaders are the response headers the datasources read besides the body
+var storedHeaders = []string{"Content-Type", "Link"}
+
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{
+		Dir: dir,
+		TTL: ttl,
+		now: time.Now,
+	}
+}
+
+// CredentialHash returns the hash of the credentials of a datasource, the
+// empty string without credentials
+func CredentialHash(username, token string) string {
+	if username == "" && token == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(username + "\x00" + token))
+	return hex.EncodeToString(sum[:])
+}
+
+// DefaultDir returns the smgr directory of the user cache directory
+func DefaultDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "smgr"), nil
+}
+
+// Transport returns a RoundTripper storing the successful GET responses of
+// the datasource key that carry an ETag or a Last-Modified header. Stored
+// responses are revalidated with If-None-Match and If-Modified-Since once
+// the TTL expired, a 304 Not Modified is answered with the stored response.
+// Requests with Cache-Control: no-store, e.g. token requests, are never stored.
+func (c *Cache) Transport(key Key, next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &transport{cache: c, key: key, next: next}
+}
+
+// Prune removes the cache files that were not used for longer than
+// olderThan, every file is removed when olderThan is zero
+func (c *Cache) Prune(olderThan time.Duration) (int, error) {
+	files, err := os.ReadDir(c.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cache: prune error: %w", err)
+	}
+
+	removed := 0
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), fileExtension) {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			return removed, fmt.Errorf("cache: prune error: %w", err)
+		}
+		if olderThan > 0 && c.now().Sub(info.ModTime()) < olderThan {
+			continue
+		}
+		if err := os.Remove(filepath.Join(c.Dir, file.Name())); err != nil {
+			return removed, fmt.Errorf("cache: prune error: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (c *Cache) path(key Key) string {
+	sum := sha256.Sum256([]byte(key.Platform + "\x00" + key.Owner + "\x00" + key.Repository + "\x00" + key.Credential))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:])+fileExtension)
+}
+
+type transport struct {
+	cache *Cache
+	key   Key
+	next  http.RoundTripper
+
+	mu      sync.Mutex
+	entries map[string]*entry
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || noStore(req.Header) {
+		return t.next.RoundTrip(req)
+	}
+
+	url := req.URL.String()
+	stored := t.lookup(url)
+	if stored != nil && t.cache.TTL > 0 && t.cache.now().Sub(stored.ValidatedAt) < t.cache.TTL {
+		klog.V(2).Infof("Cache hit for %s", req.URL.Redacted())
+		t.touch()
+		return stored.response(req), nil
+	}
+
+	if stored != nil {
+		req = req.Clone(req.Context())
+		if stored.ETag != "" && req.Header.Get("If-None-Match") == "" {
+			req.Header.Set("If-None-Match", stored.ETag)
+		}
+		if stored.LastModified != "" && req.Header.Get("If-Modified-Since") == "" {
+			req.Header.Set("If-Modified-Since", stored.LastModified)
+		}
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && stored != nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		klog.V(2).Infof("Cache revalidated %s", req.URL.Redacted())
+		stored.ValidatedAt = t.cache.now()
+		t.store(url, stored)
+		return stored.response(req), nil
+	}
+
+	if resp.StatusCode != http.StatusOK || !cacheable(resp) {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	header := http.Header{}
+	for _, name := range storedHeaders {
+		if values := resp.Header.Values(name); len(values) > 0 {
+			header[name] = values
+		}
+	}
+	t.store(url, &entry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		Header:       header,
+		Body:         body,
+		ValidatedAt:  t.cache.now(),
+	})
+	return resp, nil
+}
+
+// cacheable returns true when a response can be revalidated and may be stored
+func cacheable(resp *http.Response) bool {
+	if noStore(resp.Header) {
+		return false
+	}
+	return resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != ""
+}
+
+// noStore returns true when the Cache-Control header forbids storing
+func noStore(header http.Header) bool {
+	return strings.Contains(strings.ToLower(header.Get("Cache-Control")), "no-store")
+}
+
+func (e *entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func (t *transport) lookup(url string) *entry {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.entries == nil {
+		t.entries = t.load()
+	}
+	stored, found := t.entries[url]
+	if !found {
+		return nil
+	}
+	copied := *stored
+	return &copied
+}
+
+// load reads the cache file of the key, a missing or unreadable file is
+// an empty cache
+func (t *transport) load() map[string]*entry {
+	data, err := os.ReadFile(t.cache.path(t.key))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			klog.Warningf("cache: ignoring %s: %v", t.cache.path(t.key), err)
+		}
+		return map[string]*entry{}
+	}
+
+	file := &cacheFile{}
+	if err := json.Unmarshal(data, file); err != nil || file.Key != t.key || file.Entries == nil {
+		klog.Warningf("cache: ignoring invalid file %s", t.cache.path(t.key))
+		return map[string]*entry{}
+	}
+	return file.Entries
+}
+
+// touch marks the cache file as used for Prune, the fresh entries are
+// served without writing it
+func (t *transport) touch() {
+	now := t.cache.now()
+	if err := os.Chtimes(t.cache.path(t.key), now, now); err != nil {
+		klog.Warningf("cache: %v", err)
+	}
+}
+
+// store records the entry of url and writes the cache file, a cache that
+// cannot be written does not fail the fetch
+func (t *transport) store(url string, stored *entry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.entries[url] = stored
+	if err := t.save(); err != nil {
+		klog.Warningf("cache: %v", err)
+	}
+}
+
+func (t *transport) save() error {
+	data, err := json.Marshal(&cacheFile{Key: t.key, Entries: t.entries})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.cache.Dir, 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(t.cache.Dir, "*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), t.cache.path(t.key))
+}
@@ -0,0 +1,184 @@
+package cache
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves a tag list with an ETag and counts the requests
+// and the 304 Not Modified answers
+func newTestServer(t *testing.T, body *string) (*httptest.Server, *int, *int) {
+	t.Helper()
+	requests, notModified := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		etag := fmt.Sprintf("%q", fmt.Sprintf("%x", len(*body)))
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Link", `<http://example.com/tags?page=2>; rel="next"`)
+		fmt.Fprint(w, *body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests, &notModified
+}
+
+func get(t *testing.T, client *http.Client, url string) (string, *http.Response) {
+	t.Helper()
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body), resp
+}
+
+func TestTransport(t *testing.T) {
+	key := Key{Platform: "github", Owner: "org", Repository: "app"}
+
+	t.Run("Conditional requests", func(t *testing.T) {
+		body := `["1.0.0"]`
+		server, requests, notModified := newTestServer(t, &body)
+		cache := New(t.TempDir(), 0)
+
+		got, _ := get(t, &http.Client{Transport: cache.Transport(key, nil)}, server.URL)
+		assert.Equal(t, `["1.0.0"]`, got)
+
+		// A new run reads the stored entry from disk and revalidates it
+		got, resp := get(t, &http.Client{Transport: cache.Transport(key, nil)}, server.URL)
+		assert.Equal(t, `["1.0.0"]`, got)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, `<http://example.com/tags?page=2>; rel="next"`, resp.Header.Get("Link"))
+		assert.Equal(t, 2, *requests)
+		assert.Equal(t, 1, *notModified)
+
+		body = `["1.0.0","1.1.0"]`
+		got, _ = get(t, &http.Client{Transport: cache.Transport(key, nil)}, server.URL)
+		assert.Equal(t, `["1.0.0","1.1.0"]`, got)
+		assert.Equal(t, 1, *notModified)
+	})
+
+	t.Run("Fresh entries are served without requests", func(t *testing.T) {
+		body := `["1.0.0"]`
+		server, requests, _ := newTestServer(t, &body)
+		cache := New(t.TempDir(), time.Minute)
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		get(t, &http.Client{Transport: cache.Transport(key, nil)}, server.URL)
+		got, _ := get(t, &http.Client{Transport: cache.Transport(key, nil)}, server.URL)
+		assert.Equal(t, `["1.0.0"]`, got)
+		assert.Equal(t, 1, *requests)
+
+		now = now.Add(2 * time.Minute)
+		get(t, &http.Client{Transport: cache.Transport(key, nil)}, server.URL)
+		assert.Equal(t, 2, *requests)
+	})
+
+	t.Run("Fresh entries keep the file from being pruned", func(t *testing.T) {
+		body := `["1.0.0"]`
+		server, requests, _ := newTestServer(t, &body)
+		cache := New(t.TempDir(), 24*time.Hour)
+		now := time.Now()
+		cache.now = func() time.Time { return now }
+
+		get(t, &http.Client{Transport: cache.Transport(key, nil)}, server.URL)
+		now = now.Add(2 * time.Hour)
+		get(t, &http.Client{Transport: cache.Transport(key, nil)}, server.URL)
+		assert.Equal(t, 1, *requests)
+
+		removed, err := cache.Prune(time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, 0, removed)
+		assert.FileExists(t, cache.path(key))
+	})
+
+	t.Run("Keys are stored apart", func(t *testing.T) {
+		body := `["1.0.0"]`
+		server, _, notModified := newTestServer(t, &body)
+		cache := New(t.TempDir(), 0)
+
+		get(t, &http.Client{Transport: cache.Transport(key, nil)}, server.URL)
+		get(t, &http.Client{Transport: cache.Transport(Key{Platform: "gitea", Owner: "org", Repository: "app"}, nil)}, server.URL)
+		assert.Equal(t, 0, *notModified)
+	})
+
+	t.Run("Credentials are stored apart", func(t *testing.T) {
+		body := `["1.0.0"]`
+		server, requests, _ := newTestServer(t, &body)
+		cache := New(t.TempDir(), time.Minute)
+		withToken := key
+		withToken.Credential = CredentialHash("", "secret")
+
+		get(t, &http.Client{Transport: cache.Transport(withToken, nil)}, server.URL)
+		get(t, &http.Client{Transport: cache.Transport(key, nil)}, server.URL)
+		assert.Equal(t, 2, *requests)
+		assert.NotEqual(t, cache.path(key), cache.path(withToken))
+		assert.Empty(t, CredentialHash("", ""))
+		assert.NotEqual(t, CredentialHash("", "secret"), CredentialHash("", "other"))
+	})
+
+	t.Run("No-store requests are not stored", func(t *testing.T) {
+		body := `{"token":"abc"}`
+		server, requests, _ := newTestServer(t, &body)
+		cache := New(t.TempDir(), time.Minute)
+
+		for range 2 {
+			req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+			require.NoError(t, err)
+			req.Header.Set("Cache-Control", "no-store")
+			resp, err := (&http.Client{Transport: cache.Transport(key, nil)}).Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+		}
+		assert.Equal(t, 2, *requests)
+		assert.NoFileExists(t, cache.path(key))
+	})
+
+	t.Run("Invalid cache file", func(t *testing.T) {
+		body := `["1.0.0"]`
+		server, _, _ := newTestServer(t, &body)
+		cache := New(t.TempDir(), 0)
+		require.NoError(t, os.WriteFile(cache.path(key), []byte("{"), 0o600))
+
+		got, _ := get(t, &http.Client{Transport: cache.Transport(key, nil)}, server.URL)
+		assert.Equal(t, `["1.0.0"]`, got)
+	})
+}
+
+func TestCache_Prune(t *testing.T) {
+	dir := t.TempDir()
+	cache := New(dir, 0)
+	old, recent := cache.path(Key{Repository: "old"}), cache.path(Key{Repository: "recent"})
+	require.NoError(t, os.WriteFile(old, []byte("{}"), 0o600))
+	require.NoError(t, os.WriteFile(recent, []byte("{}"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte(""), 0o600))
+	require.NoError(t, os.Chtimes(old, time.Now().Add(-48*time.Hour), time.Now().Add(-48*time.Hour)))
+
+	removed, err := cache.Prune(24 * time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.NoFileExists(t, old)
+	assert.FileExists(t, recent)
+
+	removed, err = cache.Prune(0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.FileExists(t, filepath.Join(dir, "notes.txt"))
+
+	removed, err = New(filepath.Join(dir, "missing"), 0).Prune(0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+}
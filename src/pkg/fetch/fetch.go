@@ -2,10 +2,22 @@ package fetch
 
 import (
 	"errors"
+	"fmt"
 
+	"src/cmd/smgr/datasource/bitbucket"
+	"src/cmd/smgr/datasource/crates"
+	"src/cmd/smgr/datasource/file"
+	"src/cmd/smgr/datasource/git"
+	"src/cmd/smgr/datasource/gitea"
 	"src/cmd/smgr/datasource/github"
 	"src/cmd/smgr/datasource/gitlab"
+	"src/cmd/smgr/datasource/goproxy"
+	"src/cmd/smgr/datasource/helm"
+	"src/cmd/smgr/datasource/maven"
+	"src/cmd/smgr/datasource/npm"
 	"src/cmd/smgr/datasource/oci"
+	"src/cmd/smgr/datasource/pypi"
+	datasourceUtils "src/cmd/smgr/datasource/utils"
 	"src/cmd/smgr/models"
 	"src/cmd/smgr/utils"
 )
@@ -14,14 +26,89 @@ type Fetcher interface {
 	FetchTags() ([]models.Version, error)
 }
 
+// EntryReporter is implemented by Fetchers reading free form input,
+// it returns an error locating every entry that was not a valid version
+type EntryReporter interface {
+	EntryErrors() []error
+}
+
+// Warner is implemented by Fetchers noticing problems that do not fail the fetch
+type Warner interface {
+	Warnings() []string
+}
+
+// ReleaseLister is implemented by Fetchers reading platform releases, it
+// returns the releases of the last fetch with their flags
+type ReleaseLister interface {
+	Releases() []datasourceUtils.Release
+}
+
+// DistTagLister is implemented by Fetchers of registries naming versions
+// with tags, it returns the versions of the tags of the last fetch
+type DistTagLister interface {
+	DistTags() map[string]models.Version
+}
+
+// YankedLister is implemented by Fetchers of registries where published
+// versions can be yanked, it returns the yanked versions of the last fetch
+type YankedLister interface {
+	Yanked() []string
+}
+
+// DryRunFetcher is a Fetcher that never reaches a datasource
+type DryRunFetcher struct{}
+
+func (d *DryRunFetcher) FetchTags() ([]models.Version, error) {
+	return []models.Version{}, nil
+}
+
 func NewFetcher(config *utils.DatasourceConfig) (Fetcher, error) {
+	switch config.Kind {
+	case "", datasourceUtils.TagsKind:
+	case datasourceUtils.ReleasesKind:
+		if config.Platform != "github" && config.Platform != "dry-run" {
+			return nil, fmt.Errorf("the %s kind is only supported by the github platform", config.Kind)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported kind %q, options: %s, %s", config.Kind, datasourceUtils.TagsKind, datasourceUtils.ReleasesKind)
+	}
+
+	switch config.Platform {
+	case "npm", "goproxy", "pypi", "crates", "maven", "helm":
+		if !config.Namespace.IsEmpty() {
+			return nil, fmt.Errorf("namespaces are not supported by the %s platform, it does not list tags", config.Platform)
+		}
+	}
+
 	switch config.Platform {
 	case "github":
 		return github.NewFetcher(config), nil
 	case "gitlab":
 		return gitlab.NewFetcher(config), nil
+	case "gitea", "forgejo":
+		return gitea.NewFetcher(config), nil
+	case "bitbucket-server":
+		return bitbucket.NewFetcher(config), nil
 	case "oci":
 		return oci.NewFetcher(config), nil
+	case "git":
+		return git.NewFetcher(config), nil
+	case "file":
+		return file.NewFetcher(config), nil
+	case "npm":
+		return npm.NewFetcher(config), nil
+	case "goproxy":
+		return goproxy.NewFetcher(config), nil
+	case "pypi":
+		return pypi.NewFetcher(config), nil
+	case "crates":
+		return crates.NewFetcher(config), nil
+	case "maven":
+		return maven.NewFetcher(config), nil
+	case "helm":
+		return helm.NewFetcher(config), nil
+	case "dry-run":
+		return &DryRunFetcher{}, nil
 	default:
 		return nil, errors.New("unsupported platform")
 	}
@@ -1 +1,58 @@
 package fetch
+
+import (
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFetcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		platform string
+		wantErr  bool
+	}{
+		{name: "GitHub", platform: "github"},
+		{name: "GitLab", platform: "gitlab"},
+		{name: "Gitea", platform: "gitea"},
+		{name: "Forgejo", platform: "forgejo"},
+		{name: "Bitbucket Server", platform: "bitbucket-server"},
+		{name: "OCI", platform: "oci"},
+		{name: "Local git repository", platform: "git"},
+		{name: "Plain text file", platform: "file"},
+		{name: "npm registry", platform: "npm"},
+		{name: "Go module proxy", platform: "goproxy"},
+		{name: "PyPI", platform: "pypi"},
+		{name: "crates.io", platform: "crates"},
+		{name: "Maven repository", platform: "maven"},
+		{name: "Helm repository", platform: "helm"},
+		{name: "Dry run", platform: "dry-run"},
+		{name: "Unsupported platform", platform: "svn", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher, err := NewFetcher(&utils.DatasourceConfig{Platform: tt.platform})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, fetcher)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, fetcher)
+			}
+		})
+	}
+}
+
+func TestNewFetcherNamespace(t *testing.T) {
+	namespace := models.Namespace{Prefix: "service-a/v"}
+
+	fetcher, err := NewFetcher(&utils.DatasourceConfig{Platform: "git", Namespace: namespace})
+	assert.NoError(t, err)
+	assert.NotNil(t, fetcher)
+
+	_, err = NewFetcher(&utils.DatasourceConfig{Platform: "npm", Namespace: namespace})
+	assert.ErrorContains(t, err, "namespaces are not supported by the npm platform")
+}
@@ -0,0 +1,112 @@
+package fetch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+)
+
+// Source is a Fetcher named after the platform:location it reads
+type Source struct {
+	Name    string
+	Fetcher Fetcher
+}
+
+// SourcedVersion is a version with the names of the sources it was fetched from
+type SourcedVersion struct {
+	models.Version
+	Sources []string
+}
+
+// ParseSource parses a platform:location source into a datasource config.
+// The location is the repository of the platform, except for github which
+// takes owner/repo and helm which takes the repository URL and the chart
+// separated by #, e.g. helm:https://charts.example.com#mychart.
+func ParseSource(source string) (*utils.DatasourceConfig, error) {
+	platform, location, found := strings.Cut(strings.TrimSpace(source), ":")
+	if !found || platform == "" || location == "" {
+		return nil, fmt.Errorf("invalid source %q, expected platform:location", source)
+	}
+
+	config := &utils.DatasourceConfig{Platform: platform, Repository: location}
+	switch platform {
+	case "github":
+		owner, repository, found := strings.Cut(location, "/")
+		if !found || owner == "" || repository == "" {
+			return nil, fmt.Errorf("invalid source %q, expected github:owner/repo", source)
+		}
+		config.Owner, config.Repository = owner, repository
+	case "helm":
+		repository, chart, found := strings.Cut(location, "#")
+		if !found || repository == "" || chart == "" {
+			return nil, fmt.Errorf("invalid source %q, expected helm:url#chart", source)
+		}
+		config.Repository, config.Chart = repository, chart
+	}
+	return config, nil
+}
+
+// FetchAll fetches the versions of every source concurrently and merges
+// them. Versions of equal Semver precedence are returned once, as first
+// fetched in the order of the sources, with the names of all the sources
+// they were found in. Every failed source is reported in the error.
+func FetchAll(sources []Source) ([]SourcedVersion, error) {
+	results := make([][]models.Version, len(sources))
+	errs := make([]error, len(sources))
+
+	var wg sync.WaitGroup
+	for i, source := range sources {
+		wg.Add(1)
+		go func(i int, source Source) {
+			defer wg.Done()
+			versions, err := source.Fetcher.FetchTags()
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", source.Name, err)
+				return
+			}
+			results[i] = versions
+		}(i, source)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	merged := []SourcedVersion{}
+	positions := map[string]int{}
+	for i, versions := range results {
+		for _, version := range versions {
+			key := precedenceKey(version)
+			position, found := positions[key]
+			if !found {
+				positions[key] = len(merged)
+				merged = append(merged, SourcedVersion{Version: version, Sources: []string{sources[i].Name}})
+				continue
+			}
+			if !containsSource(merged[position].Sources, sources[i].Name) {
+				merged[position].Sources = append(merged[position].Sources, sources[i].Name)
+			}
+		}
+	}
+	return merged, nil
+}
+
+// precedenceKey identifies the versions of equal precedence, build metadata
+// is ignored as per the Semver specification
+func precedenceKey(version models.Version) string {
+	return version.Release.String() + version.Prerelease.String()
+}
+
+func containsSource(sources []string, name string) bool {
+	for _, source := range sources {
+		if source == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package fetch
+
+import (
+	"errors"
+	"testing"
+
+	"src/cmd/smgr/models"
+	"src/cmd/smgr/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type staticFetcher struct {
+	versions string
+	err      error
+}
+
+func (s staticFetcher) FetchTags() ([]models.Version, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return models.ParseVersions(s.versions)
+}
+
+func TestParseSource(t *testing.T) {
+	tests := []struct {
+		source   string
+		expected *utils.DatasourceConfig
+		wantErr  bool
+	}{
+		{source: "git:.", expected: &utils.DatasourceConfig{Platform: "git", Repository: "."}},
+		{source: "oci:ghcr.io/org/app:latest", expected: &utils.DatasourceConfig{Platform: "oci", Repository: "ghcr.io/org/app:latest"}},
+		{source: "maven:org.example:app", expected: &utils.DatasourceConfig{Platform: "maven", Repository: "org.example:app"}},
+		{source: "github:bluepr-nt/smgr", expected: &utils.DatasourceConfig{Platform: "github", Owner: "bluepr-nt", Repository: "smgr"}},
+		{source: "helm:https://charts.example.com#mychart", expected: &utils.DatasourceConfig{Platform: "helm", Repository: "https://charts.example.com", Chart: "mychart"}},
+		{source: "github:smgr", wantErr: true},
+		{source: "helm:https://charts.example.com", wantErr: true},
+		{source: "git", wantErr: true},
+		{source: ":.", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			config, err := ParseSource(tt.source)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, config)
+		})
+	}
+}
+
+func TestFetchAll(t *testing.T) {
+	t.Run("Merge by precedence", func(t *testing.T) {
+		versions, err := FetchAll([]Source{
+			{Name: "git:.", Fetcher: staticFetcher{versions: "1.0.0 1.1.0+build.1 1.2.0-rc.1"}},
+			{Name: "oci:app", Fetcher: staticFetcher{versions: "1.1.0+build.2 1.0.0 0.9.0"}},
+		})
+		require.NoError(t, err)
+
+		got := map[string][]string{}
+		for _, version := range versions {
+			got[version.String()] = version.Sources
+		}
+		assert.Equal(t, map[string][]string{
+			"1.0.0":         {"git:.", "oci:app"},
+			"1.1.0+build.1": {"git:.", "oci:app"},
+			"1.2.0-rc.1":    {"git:."},
+			"0.9.0":         {"oci:app"},
+		}, got)
+	})
+
+	t.Run("Failed sources", func(t *testing.T) {
+		_, err := FetchAll([]Source{
+			{Name: "git:.", Fetcher: staticFetcher{versions: "1.0.0"}},
+			{Name: "oci:app", Fetcher: staticFetcher{err: errors.New("unauthorized")}},
+			{Name: "npm:pkg", Fetcher: staticFetcher{err: errors.New("not found")}},
+		})
+		assert.ErrorContains(t, err, "oci:app: unauthorized")
+		assert.ErrorContains(t, err, "npm:pkg: not found")
+	})
+}
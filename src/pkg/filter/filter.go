@@ -1,10 +1,10 @@
 package filter
 
 import (
+	"fmt"
+	"sort"
 	"src/cmd/smgr/models"
 	"strconv"
-
-	"github.com/blang/semver/v4"
 )
 
 type FilterFunc func(versions []models.Version) ([]models.Version, error)
@@ -23,6 +23,9 @@ func ApplyFilters(versions []models.Version, filters ...FilterFunc) (models.Vers
 	return filtered, nil
 }
 
+// Highest returns a filter function that keeps the highest version
+// according to the Semver precedence, see isPreferred for the versions of
+// equal precedence
 func Highest() FilterFunc {
 	return func(versions []models.Version) ([]models.Version, error) {
 		if len(versions) == 0 {
@@ -31,10 +34,7 @@ func Highest() FilterFunc {
 
 		highest := versions[0]
 		for _, version := range versions[1:] {
-			semverVersion, _ := semver.ParseTolerant(version.String())
-			highestSemver, _ := semver.ParseTolerant(highest.String())
-			semver.Parse("")
-			if semverVersion.GT(highestSemver) {
+			if version.IsHigherThan(highest) || isPreferred(version, highest) {
 				highest = version
 			}
 		}
@@ -43,6 +43,212 @@ func Highest() FilterFunc {
 	}
 }
 
+// Lowest returns a filter function that
+// keeps the lowest version according to the Semver precedence
+func Lowest() FilterFunc {
+	return func(versions []models.Version) ([]models.Version, error) {
+		if len(versions) == 0 {
+			return versions, &models.EmptyVersionListError{}
+		}
+
+		lowest := versions[0]
+		for _, version := range versions[1:] {
+			if lowest.IsHigherThan(version) || isPreferred(version, lowest) {
+				lowest = version
+			}
+		}
+
+		return []models.Version{lowest}, nil
+	}
+}
+
+// isPreferred returns true if a and b have the same precedence and a wins
+// the tiebreak, whatever their order: the lowest build metadata, no build
+// metadata first, then the lowest string e.g. 1.2.3 before v1.2.3
+func isPreferred(a, b models.Version) bool {
+	if !a.IsEqualTo(b) {
+		return false
+	}
+	if b.BuildMetadata.IsHigherThan(a.BuildMetadata) {
+		return true
+	}
+	if a.BuildMetadata.IsHigherThan(b.BuildMetadata) {
+		return false
+	}
+	return a.String() < b.String()
+}
+
+// DedupePolicy selects which versions of equal precedence, that only differ
+// by their build metadata or prefix, are kept
+type DedupePolicy string
+
+const (
+	DedupeFirst DedupePolicy = "first"
+	DedupeLast  DedupePolicy = "last"
+	DedupeAll   DedupePolicy = "all"
+)
+
+// ParseDedupePolicy parses the name of a DedupePolicy
+func ParseDedupePolicy(policy string) (DedupePolicy, error) {
+	switch DedupePolicy(policy) {
+	case DedupeFirst, DedupeLast, DedupeAll:
+		return DedupePolicy(policy), nil
+	default:
+		return "", fmt.Errorf("invalid dedupe policy %q, options: %s, %s, %s", policy, DedupeFirst, DedupeLast, DedupeAll)
+	}
+}
+
+// Dedupe returns a filter function that keeps the first or last version of
+// every precedence in the input order, or all of them
+func Dedupe(policy DedupePolicy) FilterFunc {
+	return func(versions []models.Version) ([]models.Version, error) {
+		if policy == DedupeAll {
+			return versions, nil
+		}
+
+		kept := map[string]int{}
+		for i, version := range versions {
+			key := precedenceKey(version)
+			if _, found := kept[key]; found && policy == DedupeFirst {
+				continue
+			}
+			kept[key] = i
+		}
+
+		var filtered []models.Version
+		for i, version := range versions {
+			if kept[precedenceKey(version)] == i {
+				filtered = append(filtered, version)
+			}
+		}
+
+		return filtered, nil
+	}
+}
+
+// precedenceKey identifies the versions of equal precedence, build metadata
+// is ignored as per the Semver specification
+func precedenceKey(version models.Version) string {
+	return version.Release.String() + version.Prerelease.String()
+}
+
+// Order is the order versions are sorted in
+type Order string
+
+const (
+	Ascending  Order = "asc"
+	Descending Order = "desc"
+)
+
+// ParseOrder parses the name of an Order
+func ParseOrder(order string) (Order, error) {
+	switch Order(order) {
+	case Ascending, Descending:
+		return Order(order), nil
+	default:
+		return "", fmt.Errorf("invalid order %q, options: %s, %s", order, Ascending, Descending)
+	}
+}
+
+// Sort returns a filter function that sorts versions by their Semver
+// precedence, versions of equal precedence keep their input order
+func Sort(order Order) FilterFunc {
+	return func(versions []models.Version) ([]models.Version, error) {
+		sorted := append([]models.Version{}, versions...)
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if order == Descending {
+				return sorted[i].IsHigherThan(sorted[j])
+			}
+			return sorted[j].IsHigherThan(sorted[i])
+		})
+
+		return sorted, nil
+	}
+}
+
+// Limit returns a filter function that
+// keeps the first n versions
+func Limit(n int) FilterFunc {
+	return func(versions []models.Version) ([]models.Version, error) {
+		if n < 1 {
+			return nil, fmt.Errorf("the limit MUST be at least 1, got: %d", n)
+		}
+
+		return versions[:min(n, len(versions))], nil
+	}
+}
+
+// Grouping selects the streams versions are grouped by
+type Grouping string
+
+const (
+	GroupByMajor           Grouping = "major"
+	GroupByMinor           Grouping = "minor"
+	GroupByPrereleaseLabel Grouping = "prerelease-label"
+)
+
+// ParseGrouping parses the name of a Grouping
+func ParseGrouping(grouping string) (Grouping, error) {
+	switch Grouping(grouping) {
+	case GroupByMajor, GroupByMinor, GroupByPrereleaseLabel:
+		return Grouping(grouping), nil
+	default:
+		return "", fmt.Errorf("invalid grouping %q, options: %s, %s, %s", grouping, GroupByMajor, GroupByMinor, GroupByPrereleaseLabel)
+	}
+}
+
+// Key returns the group of a version, e.g. 1.2 by minor or rc by
+// prerelease label, releases have the empty prerelease label
+func (g Grouping) Key(version models.Version) string {
+	switch g {
+	case GroupByMajor:
+		return version.Release.Major.String()
+	case GroupByMinor:
+		return version.Release.Major.String() + "." + version.Release.Minor.String()
+	default:
+		if version.IsRelease() {
+			return ""
+		}
+		return version.Prerelease.Identifiers[0].Value()
+	}
+}
+
+// HighestPerGroup returns a filter function that keeps
+// the top highest versions of every group, in their input order
+func HighestPerGroup(grouping Grouping, top int) FilterFunc {
+	return func(versions []models.Version) ([]models.Version, error) {
+		if top < 1 {
+			return nil, fmt.Errorf("the number of versions per group MUST be at least 1, got: %d", top)
+		}
+
+		groups := map[string][]int{}
+		for i, version := range versions {
+			key := grouping.Key(version)
+			groups[key] = append(groups[key], i)
+		}
+
+		kept := make([]bool, len(versions))
+		for _, indexes := range groups {
+			sort.SliceStable(indexes, func(i, j int) bool {
+				a, b := versions[indexes[i]], versions[indexes[j]]
+				return a.IsHigherThan(b) || isPreferred(a, b)
+			})
+			for _, index := range indexes[:min(top, len(indexes))] {
+				kept[index] = true
+			}
+		}
+
+		var filtered []models.Version
+		for i, version := range versions {
+			if kept[i] {
+				filtered = append(filtered, version)
+			}
+		}
+
+		return filtered, nil
+	}
+}
+
 func GetHighestStreamVersion(versions []models.Version, streamPattern models.VersionPattern) (models.Version, error) {
 	var err error
 	streamFilter := VersionPatternFilter(streamPattern)
@@ -101,6 +307,82 @@ func VersionPatternFilter(pattern models.VersionPattern) FilterFunc {
 	}
 }
 
+// ExcludePatternFilter returns a filter function that
+// drops the versions matching any of the VersionPatterns
+func ExcludePatternFilter(patterns ...models.VersionPattern) FilterFunc {
+	return func(versions []models.Version) ([]models.Version, error) {
+		var filtered []models.Version
+
+		for _, version := range versions {
+			excluded := false
+			for _, pattern := range patterns {
+				matched, err := VersionPatternFilter(pattern)([]models.Version{version})
+				if err != nil {
+					return nil, err
+				}
+				if len(matched) > 0 {
+					excluded = true
+					break
+				}
+			}
+
+			if !excluded {
+				filtered = append(filtered, version)
+			}
+		}
+
+		return filtered, nil
+	}
+}
+
+// ReleaseOnly returns a filter function that
+// filters release versions, prerelease versions are dropped
+func ReleaseOnly() FilterFunc {
+	return func(versions []models.Version) ([]models.Version, error) {
+		var filtered []models.Version
+
+		for _, version := range versions {
+			if version.IsRelease() {
+				filtered = append(filtered, version)
+			}
+		}
+
+		return filtered, nil
+	}
+}
+
+// PrereleaseOnly returns a filter function that
+// filters prerelease versions, release versions are dropped
+func PrereleaseOnly() FilterFunc {
+	return func(versions []models.Version) ([]models.Version, error) {
+		var filtered []models.Version
+
+		for _, version := range versions {
+			if !version.IsRelease() {
+				filtered = append(filtered, version)
+			}
+		}
+
+		return filtered, nil
+	}
+}
+
+// ConstraintFilter returns a filter function that
+// filters versions satisfying a Constraint
+func ConstraintFilter(constraint models.Constraint) FilterFunc {
+	return func(versions []models.Version) ([]models.Version, error) {
+		var filtered []models.Version
+
+		for _, version := range versions {
+			if constraint.Check(version) {
+				filtered = append(filtered, version)
+			}
+		}
+
+		return filtered, nil
+	}
+}
+
 // ReleasePatternFilter returns a filter function that
 // filters versions based on a ReleasePattern
 // all release and prelease versions are returned
@@ -109,13 +391,7 @@ func ReleasePatternFilter(pattern models.ReleasePattern) FilterFunc {
 		var filtered []models.Version
 
 		for _, version := range versions {
-			if pattern.Major.Value() != "*" && version.Release.Major.String() != pattern.Major.Value() {
-				continue
-			}
-			if pattern.Minor.Value() != "*" && version.Release.Minor.String() != pattern.Minor.Value() {
-				continue
-			}
-			if pattern.Patch.Value() != "*" && version.Release.Patch.String() != pattern.Patch.Value() {
+			if !pattern.Match(version.Release) {
 				continue
 			}
 
@@ -147,31 +423,48 @@ func PrereleasePatternFilter(pattern models.PRVersionPattern) FilterFunc {
 	}
 }
 
-// GetValidVersions returns a list of valid versions from one or more string lists of versions
-// The versions are split by comma or space and then parsed into a Version struct
-func GetValidVersions(stringVersionsList ...string) []models.Version {
-	var versions []models.Version
+// ParseResult holds the valid versions of version lists and the errors of
+// their invalid entries
+type ParseResult struct {
+	Versions []models.Version
+	Errors   []error
+}
+
+// Append adds the versions and errors of another result
+func (r *ParseResult) Append(result ParseResult) {
+	r.Versions = append(r.Versions, result.Versions...)
+	r.Errors = append(r.Errors, result.Errors...)
+}
+
+// ParseVersionLists parses one or more comma or space separated lists of
+// versions, every invalid entry is recorded as an *models.InvalidVersionError
+func ParseVersionLists(stringVersionsList ...string) ParseResult {
+	result := ParseResult{Versions: []models.Version{}, Errors: []error{}}
 	for _, stringVersions := range stringVersionsList {
 		for _, stringVersion := range models.SplitVersions(stringVersions) {
-			version, _ := models.ParseVersion(stringVersion)
-			versions = append(versions, version)
+			if stringVersion == "" {
+				continue
+			}
+			version, err := models.ParseVersion(stringVersion)
+			if err != nil {
+				result.Errors = append(result.Errors, &models.InvalidVersionError{Entry: stringVersion, Err: err})
+				continue
+			}
+			result.Versions = append(result.Versions, version)
 		}
 	}
-	return versions
+	return result
 }
 
-func matchPrerelease(prIdentifiersPattern []models.PRIdentifierPattern, prerelease models.PRVersion) bool {
-	if len(prIdentifiersPattern) != len(prerelease.Identifiers) {
-		return false
-	}
-
-	for i, prIdentifierPattern := range prIdentifiersPattern {
-		if prIdentifierPattern.Value() != "*" && prIdentifierPattern.Value() != prerelease.Identifiers[i].Value() {
-			return false
-		}
-	}
+// GetValidVersions returns a list of valid versions from one or more string lists of versions
+// The versions are split by comma or space and then parsed into a Version struct,
+// invalid versions are dropped
+func GetValidVersions(stringVersionsList ...string) []models.Version {
+	return ParseVersionLists(stringVersionsList...).Versions
+}
 
-	return true
+func matchPrerelease(prIdentifiersPattern []models.PRIdentifierPattern, prerelease models.PRVersion) bool {
+	return models.PRVersionPattern{Identifiers: prIdentifiersPattern}.Match(prerelease)
 }
 
 func toUint(s string) uint64 {
@@ -201,15 +494,5 @@ func BuildMetadataFilter(pattern models.BuildMetadataPattern) FilterFunc {
 }
 
 func matchBuildMetadata(buildIdentifiersPattern []models.BuildIdentifierPattern, buildMetadata models.BuildMetadata) bool {
-	if len(buildIdentifiersPattern) != len(buildMetadata.Identifiers) {
-		return false
-	}
-
-	for i, buildIdentifierPattern := range buildIdentifiersPattern {
-		if buildIdentifierPattern.Value() != "*" && buildIdentifierPattern.Value() != buildMetadata.Identifiers[i].String() {
-			return false
-		}
-	}
-
-	return true
+	return models.BuildMetadataPattern{Identifiers: buildIdentifiersPattern}.Match(buildMetadata)
 }
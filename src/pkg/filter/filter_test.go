@@ -2,8 +2,10 @@ package filter
 
 import (
 	"errors"
+	"fmt"
 	"src/cmd/smgr/models"
 	"src/cmd/smgr/testutils"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -74,6 +76,68 @@ func TestHighest(t *testing.T) {
 	}
 }
 
+func TestHighestAndLowestTiebreak(t *testing.T) {
+	versions := []models.Version{
+		testutils.NewVersion("v1.2.3+b"),
+		testutils.NewVersion("1.2.3+b"),
+		testutils.NewVersion("1.2.3+a.1"),
+		testutils.NewVersion("1.2.3+a"),
+		testutils.NewVersion("1.0.0+b"),
+		testutils.NewVersion("1.0.0"),
+	}
+	reversed := []models.Version{}
+	for i := len(versions) - 1; i >= 0; i-- {
+		reversed = append(reversed, versions[i])
+	}
+
+	for _, input := range [][]models.Version{versions, reversed} {
+		highest, err := ApplyFilters(input, Highest())
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3+a", highest.String())
+
+		lowest, err := ApplyFilters(input, Lowest())
+		assert.NoError(t, err)
+		assert.Equal(t, "1.0.0", lowest.String())
+
+		perGroup, err := ApplyFilters(input, HighestPerGroup(GroupByMajor, 2))
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"1.2.3+a", "1.2.3+a.1"}, strings.Fields(perGroup.String()))
+	}
+
+	highest, err := ApplyFilters([]models.Version{testutils.NewVersion("v1.2.3"), testutils.NewVersion("1.2.3")}, Highest())
+	assert.NoError(t, err)
+	assert.Equal(t, "1.2.3", highest.String())
+}
+
+func TestDedupe(t *testing.T) {
+	versions := []models.Version{
+		testutils.NewVersion("1.2.3+b"),
+		testutils.NewVersion("1.2.2"),
+		testutils.NewVersion("1.2.3+a"),
+		testutils.NewVersion("1.2.3-rc.1+a"),
+		testutils.NewVersion("1.2.3"),
+	}
+
+	tests := []struct {
+		policy DedupePolicy
+		want   string
+	}{
+		{policy: DedupeFirst, want: "1.2.3+b 1.2.2 1.2.3-rc.1+a"},
+		{policy: DedupeLast, want: "1.2.2 1.2.3-rc.1+a 1.2.3"},
+		{policy: DedupeAll, want: "1.2.3+b 1.2.2 1.2.3+a 1.2.3-rc.1+a 1.2.3"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			got, err := ApplyFilters(versions, Dedupe(tt.policy))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+
+	_, err := ParseDedupePolicy("none")
+	assert.Error(t, err)
+}
+
 func TestGetHighestStreamVersion(t *testing.T) {
 	type args struct {
 		versions      []models.Version
@@ -301,6 +365,66 @@ func TestVersionPatternFilter(t *testing.T) {
 				testutils.NewVersion("1.2.3-alpha.beta"),
 			},
 		},
+		{
+			name:    "Minor range match",
+			pattern: testutils.NewVersionPattern("1.[2-5].*"),
+			versions: []models.Version{
+				testutils.NewVersion("1.1.0"),
+				testutils.NewVersion("1.2.0"),
+				testutils.NewVersion("1.5.3"),
+				testutils.NewVersion("1.6.0"),
+				testutils.NewVersion("1.3.0-rc.1"),
+			},
+			want: []models.Version{
+				testutils.NewVersion("1.2.0"),
+				testutils.NewVersion("1.5.3"),
+			},
+		},
+		{
+			name:    "Minor set match with an x wildcard",
+			pattern: testutils.NewVersionPattern("1.{2,4}.x"),
+			versions: []models.Version{
+				testutils.NewVersion("1.2.1"),
+				testutils.NewVersion("1.3.0"),
+				testutils.NewVersion("1.4.0"),
+				testutils.NewVersion("2.4.0"),
+			},
+			want: []models.Version{
+				testutils.NewVersion("1.2.1"),
+				testutils.NewVersion("1.4.0"),
+			},
+		},
+		{
+			name:    "Prerelease prefix wildcard match",
+			pattern: testutils.NewVersionPattern("*.*.*-rc*"),
+			versions: []models.Version{
+				testutils.NewVersion("1.0.0-rc1"),
+				testutils.NewVersion("1.0.0-rc"),
+				testutils.NewVersion("1.0.0-rc.1"),
+				testutils.NewVersion("1.0.0-beta1"),
+				testutils.NewVersion("1.0.0"),
+			},
+			want: []models.Version{
+				testutils.NewVersion("1.0.0-rc1"),
+				testutils.NewVersion("1.0.0-rc"),
+			},
+		},
+		{
+			name:    "Prerelease rest wildcard match",
+			pattern: testutils.NewVersionPattern("*.*.*-rc.**"),
+			versions: []models.Version{
+				testutils.NewVersion("1.0.0-rc"),
+				testutils.NewVersion("1.0.0-rc.1"),
+				testutils.NewVersion("1.0.0-rc.1.2"),
+				testutils.NewVersion("1.0.0-beta.1"),
+				testutils.NewVersion("1.0.0"),
+			},
+			want: []models.Version{
+				testutils.NewVersion("1.0.0-rc"),
+				testutils.NewVersion("1.0.0-rc.1"),
+				testutils.NewVersion("1.0.0-rc.1.2"),
+			},
+		},
 		{
 			name:    "Patch wildcard match",
 			pattern: testutils.NewVersionPattern("1.2.*-alpha.beta"),
@@ -417,3 +541,174 @@ func TestVersionPatternFilter(t *testing.T) {
 		})
 	}
 }
+
+func TestParseVersionLists(t *testing.T) {
+	result := ParseVersionLists("1.0.0, bad.version, v1.1.0", "2.0.0  1.2")
+
+	got := []string{}
+	for _, version := range result.Versions {
+		got = append(got, version.String())
+	}
+	assert.Equal(t, []string{"1.0.0", "v1.1.0", "2.0.0"}, got)
+
+	assert.Len(t, result.Errors, 2)
+	var invalidVersion *models.InvalidVersionError
+	assert.True(t, errors.As(result.Errors[0], &invalidVersion))
+	assert.Equal(t, "bad.version", invalidVersion.Entry)
+	assert.ErrorContains(t, result.Errors[1], `invalid version "1.2"`)
+
+	assert.Equal(t, result.Versions, GetValidVersions("1.0.0, bad.version, v1.1.0", "2.0.0  1.2"))
+}
+
+func TestConstraintFilter(t *testing.T) {
+	constraint, err := models.ParseConstraint(">=1.0.0 <2.0.0 || ^3.1")
+	assert.NoError(t, err)
+
+	versions := []models.Version{
+		testutils.NewVersion("0.9.0"),
+		testutils.NewVersion("1.0.0"),
+		testutils.NewVersion("1.5.0-rc.1"),
+		testutils.NewVersion("1.9.0"),
+		testutils.NewVersion("2.0.0"),
+		testutils.NewVersion("3.0.0"),
+		testutils.NewVersion("3.4.0"),
+	}
+	got, err := ApplyFilters(versions, ConstraintFilter(constraint))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0 1.9.0 3.4.0", got.String())
+}
+
+func TestExcludePatternFilter(t *testing.T) {
+	versions := []models.Version{
+		testutils.NewVersion("1.9.0"),
+		testutils.NewVersion("2.0.0"),
+		testutils.NewVersion("2.0.1"),
+		testutils.NewVersion("2.1.0"),
+		testutils.NewVersion("2.1.1-nightly.20240101"),
+		testutils.NewVersion("2.1.1-rc.1"),
+	}
+
+	got, err := ApplyFilters(versions,
+		VersionPatternFilter(testutils.NewVersionPattern("2.*.*")),
+		ExcludePatternFilter(testutils.NewVersionPattern("2.0.*"), testutils.NewVersionPattern("*.*.*-nightly.*")),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "2.1.0", got.String())
+
+	got, err = ApplyFilters(versions, ExcludePatternFilter(testutils.NewVersionPattern("2.0.*"), testutils.NewVersionPattern("*.*.*-nightly.*")))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.9.0 2.1.0 2.1.1-rc.1", got.String())
+
+	got, err = ApplyFilters(versions, ExcludePatternFilter())
+	assert.NoError(t, err)
+	assert.Equal(t, models.VersionSlice(versions).String(), got.String())
+
+	got, err = ApplyFilters(versions, ExcludePatternFilter(testutils.NewVersionPattern("*.*.*")))
+	assert.NoError(t, err)
+	assert.Equal(t, "2.1.1-nightly.20240101 2.1.1-rc.1", got.String())
+}
+
+func TestReleaseOnlyAndPrereleaseOnly(t *testing.T) {
+	versions := []models.Version{
+		testutils.NewVersion("1.0.0"),
+		testutils.NewVersion("2.0.0-rc.1"),
+		testutils.NewVersion("2.0.0"),
+		testutils.NewVersion("2.1.0-alpha.1"),
+	}
+
+	releases, err := ApplyFilters(versions, ReleaseOnly())
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0 2.0.0", releases.String())
+
+	prereleases, err := ApplyFilters(versions, PrereleaseOnly())
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0.0-rc.1 2.1.0-alpha.1", prereleases.String())
+
+	_, err = ApplyFilters(versions, PrereleaseOnly(), ReleaseOnly(), Highest())
+	assert.Error(t, err)
+}
+
+func TestHighestPerGroup(t *testing.T) {
+	versions := []models.Version{
+		testutils.NewVersion("1.0.0"),
+		testutils.NewVersion("1.0.3"),
+		testutils.NewVersion("1.1.0"),
+		testutils.NewVersion("1.1.2"),
+		testutils.NewVersion("2.0.0-alpha.1"),
+		testutils.NewVersion("2.0.0-rc.1"),
+		testutils.NewVersion("2.0.0-alpha.2"),
+		testutils.NewVersion("2.0.0"),
+	}
+
+	tests := []struct {
+		grouping Grouping
+		top      int
+		want     string
+		wantErr  bool
+	}{
+		{grouping: GroupByMajor, top: 1, want: "1.1.2 2.0.0"},
+		{grouping: GroupByMinor, top: 1, want: "1.0.3 1.1.2 2.0.0"},
+		{grouping: GroupByMinor, top: 2, want: "1.0.0 1.0.3 1.1.0 1.1.2 2.0.0-rc.1 2.0.0"},
+		{grouping: GroupByPrereleaseLabel, top: 1, want: "2.0.0-rc.1 2.0.0-alpha.2 2.0.0"},
+		{grouping: GroupByMajor, top: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s top %d", tt.grouping, tt.top), func(t *testing.T) {
+			got, err := ApplyFilters(versions, HighestPerGroup(tt.grouping, tt.top))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got.String())
+		})
+	}
+}
+
+func TestParseGrouping(t *testing.T) {
+	grouping, err := ParseGrouping("minor")
+	assert.NoError(t, err)
+	assert.Equal(t, GroupByMinor, grouping)
+
+	_, err = ParseGrouping("patch")
+	assert.ErrorContains(t, err, `invalid grouping "patch"`)
+}
+
+func TestSortLimitAndLowest(t *testing.T) {
+	versions := []models.Version{
+		testutils.NewVersion("1.0.0-beta.11"),
+		testutils.NewVersion("1.0.0"),
+		testutils.NewVersion("1.0.0-alpha.beta"),
+		testutils.NewVersion("1.0.0-rc.1"),
+		testutils.NewVersion("1.0.0-alpha"),
+		testutils.NewVersion("1.0.0-beta.2"),
+		testutils.NewVersion("1.0.0-alpha.1"),
+		testutils.NewVersion("1.0.0-beta"),
+	}
+	ascending := "1.0.0-alpha 1.0.0-alpha.1 1.0.0-alpha.beta 1.0.0-beta 1.0.0-beta.2 1.0.0-beta.11 1.0.0-rc.1 1.0.0"
+
+	sorted, err := ApplyFilters(versions, Sort(Ascending))
+	assert.NoError(t, err)
+	assert.Equal(t, ascending, sorted.String())
+	assert.Equal(t, "1.0.0-beta.11", versions[0].String(), "the input is not modified")
+
+	sorted, err = ApplyFilters(versions, Sort(Descending), Limit(3))
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0 1.0.0-rc.1 1.0.0-beta.11", sorted.String())
+
+	lowest, err := ApplyFilters(versions, Lowest())
+	assert.NoError(t, err)
+	assert.Equal(t, "1.0.0-alpha", lowest.String())
+
+	limited, err := ApplyFilters(versions[:2], Limit(5))
+	assert.NoError(t, err)
+	assert.Len(t, limited, 2)
+
+	_, err = ApplyFilters(versions, Limit(0))
+	assert.Error(t, err)
+	_, err = ApplyFilters([]models.Version{}, Lowest())
+	assert.Error(t, err)
+
+	_, err = ParseOrder("random")
+	assert.ErrorContains(t, err, `invalid order "random"`)
+}
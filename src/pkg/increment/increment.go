@@ -8,6 +8,8 @@ import (
 	"strconv"
 )
 
+// IncrementVersion returns the next version of the stream, written with the
+// prefix of the highest source version
 func IncrementVersion(sourceVersions []models.Version, streamPattern models.VersionPattern, increment models.Increment) (incrementedVersion models.Version, err error) {
 	if streamPattern.IsEmpty() {
 		streamPattern, _ = models.ParseVersionPattern("*.*.*")
@@ -20,8 +22,14 @@ func IncrementVersion(sourceVersions []models.Version, streamPattern models.Vers
 		incrementedVersion, err = IncrementReleaseToStream(sourceVersions, streamPattern, increment)
 
 	}
+	if err != nil {
+		return incrementedVersion, err
+	}
 
-	return incrementedVersion, err
+	if highest, err := filter.ApplyFilters(sourceVersions, filter.Highest()); err == nil {
+		incrementedVersion.Prefix = highest[0].Prefix
+	}
+	return incrementedVersion, nil
 }
 
 func IncrementRelease(sourceVersion models.Version, increment models.Increment) models.Version {
@@ -653,6 +653,27 @@ func TestIncrementVersion(t *testing.T) {
 			wantIncrementedVersion: testutils.NewVersion("1.0.0-alpha.4"),
 			wantErr:                false,
 		},
+		{
+			name: "Increment keeps the prefix of the highest source version",
+			sourceVersions: []models.Version{
+				testutils.NewVersion("1.0.0"),
+				testutils.NewVersion("v1.1.0"),
+			},
+			streamPattern:          testutils.NewVersionPattern("1.*.*"),
+			increment:              models.Minor,
+			wantIncrementedVersion: testutils.NewVersion("v1.2.0"),
+			wantErr:                false,
+		},
+		{
+			name: "Increment to a new stream keeps the prefix of the highest source version",
+			sourceVersions: []models.Version{
+				testutils.NewVersion("v1.0.0"),
+			},
+			streamPattern:          testutils.NewVersionPattern("2.0.*"),
+			increment:              models.Patch,
+			wantIncrementedVersion: testutils.NewVersion("v2.0.0"),
+			wantErr:                false,
+		},
 		{
 			name:                   "Increment with PreRelease stream pattern and no source versions",
 			sourceVersions:         []models.Version{},
@@ -1,8 +1,39 @@
 package utils
 
+import (
+	"net/http"
+
+	"src/cmd/smgr/models"
+)
+
 type DatasourceConfig struct {
 	Owner      string
 	Repository string
+	Username   string
 	Token      string
 	Platform   string
+	// BaseURL is the address of a self-hosted platform instance, the API URL
+	// of a GitHub Enterprise Server instance for github
+	BaseURL string
+	// UploadURL is the upload API URL of a GitHub Enterprise Server instance
+	UploadURL string
+	// TokenType selects how the token is sent when a platform supports several kinds
+	TokenType string
+	// Ref restricts the tags to those reachable from a git revision
+	Ref string
+	// TagPrefix is the prefix tags must start with, it is stripped before parsing
+	TagPrefix string
+	// Namespace is the template of the tags of a monorepo component, the tags
+	// of other namespaces are dropped and the template is stripped before parsing
+	Namespace models.Namespace
+	// Kind selects what the versions are read from on platforms offering
+	// several sources, tags by default or releases
+	Kind string
+	// Chart is the name of a chart in a Helm repository index
+	Chart string
+	// AppVersion reads the appVersion of Helm charts instead of their version
+	AppVersion bool
+	// Transport sends the requests of the HTTP datasources, e.g. through the
+	// tag cache, http.DefaultTransport is used when nil
+	Transport http.RoundTripper
 }